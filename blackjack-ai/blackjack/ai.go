@@ -12,7 +12,34 @@ type AI interface {
 	Results(hand [][]deck.Card, dealer []deck.Card)
 }
 
-type dealerAI struct{}
+// MoveErrorHandler can be implemented by an AI to be told why a move it
+// returned from Play was refused. The AI is then asked to play the same hand
+// again; after a few illegal moves in a row the hand is stood.
+type MoveErrorHandler interface {
+	MoveError(err error)
+}
+
+// Insurer can be implemented by an AI to be offered insurance whenever the
+// dealer shows an Ace. Returning true with a blackjack takes even money.
+type Insurer interface {
+	Insurance(hand []deck.Card, dealer deck.Card) bool
+}
+
+// TableWatcher can be implemented by an AI to be shown, at the end of every
+// round, the final hands of the other seats at the table as well as its own.
+type TableWatcher interface {
+	Watch(hands [][]deck.Card, dealer []deck.Card)
+}
+
+// ShuffleWatcher can be implemented by an AI to be told whenever the shoe is
+// reshuffled, e.g. to reset a count.
+type ShuffleWatcher interface {
+	Shuffled()
+}
+
+type dealerAI struct {
+	hitSoft17 bool
+}
 
 func (ai dealerAI) Bet() int {
 	// noop
@@ -21,7 +48,7 @@ func (ai dealerAI) Bet() int {
 
 func (ai dealerAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	dScore := Score(hand...)
-	if dScore <= 16 || (dScore == 17 && Soft(hand...)) {
+	if dScore <= 16 || (ai.hitSoft17 && dScore == 17 && Soft(hand...)) {
 		return MoveHit
 	}
 	return MoveStand
@@ -36,17 +63,23 @@ func HumanAI() AI {
 type humanAI struct{}
 
 func (ai humanAI) Bet() int {
-	var input int
-	fmt.Println("How much do you want to bet?")
-	fmt.Scanf("%d\n", &input)
-	return input
+	for {
+		var input int
+		fmt.Println("How much do you want to bet?")
+		fmt.Scanf("%d\n", &input)
+		if input > 0 {
+			return input
+		}
+		fmt.Println("Invalid bet:", input)
+	}
 }
 
 func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	for {
-		fmt.Println("Player:", Hand(hand))
-		fmt.Println("Dealer:", dealer)
-		fmt.Println("What will you do? (h)it, (s)tand")
+		fmt.Println("Player:")
+		fmt.Print(deck.Art(true, hand...))
+		fmt.Println("Dealer:", deck.Symbols(true, dealer))
+		fmt.Println("What will you do? (h)it, (s)tand, (d)ouble, s(p)lit, su(r)render")
 		var input string
 		fmt.Scanf("%s\n", &input)
 		switch input {
@@ -54,12 +87,44 @@ func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 			return MoveHit
 		case "s":
 			return MoveStand
+		case "d":
+			return MoveDouble
+		case "p":
+			return MoveSplit
+		case "r":
+			return MoveSurrender
 		default:
 			fmt.Println("Invalid option:", input)
 		}
 	}
 }
 
+func (ai humanAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	offer := "insurance"
+	if Score(hand...) == 21 {
+		offer = "even money"
+	}
+	for {
+		fmt.Println("Player:", deck.Symbols(true, hand...))
+		fmt.Println("Dealer:", deck.Symbols(true, dealer))
+		fmt.Printf("The dealer shows an Ace. Do you want %s? (y)es, (n)o\n", offer)
+		var input string
+		fmt.Scanf("%s\n", &input)
+		switch input {
+		case "y":
+			return true
+		case "n":
+			return false
+		default:
+			fmt.Println("Invalid option:", input)
+		}
+	}
+}
+
+func (ai humanAI) MoveError(err error) {
+	fmt.Println(err)
+}
+
 func (ai humanAI) Results(hand [][]deck.Card, dealer []deck.Card) {
 	fmt.Println("==FINAL HANDS==")
 	var player Hand
@@ -68,6 +133,135 @@ func (ai humanAI) Results(hand [][]deck.Card, dealer []deck.Card) {
 			player = append(player, v)
 		}
 	}
-	fmt.Println("Player: ", player, "\nScore: ", Score(player...))
-	fmt.Println("Dealer:", Hand(dealer), "\nScore: ", Score(dealer...))
+	fmt.Println("Player:", deck.Symbols(true, player...), "\nScore: ", Score(player...))
+	fmt.Println("Dealer:", deck.Symbols(true, dealer...), "\nScore: ", Score(dealer...))
+}
+
+// BasicStrategyAI plays a strategy chart, betting Unit, or 1 if unset, every
+// round. Strategy defaults to DefaultStrategy(Rules). Rules must be the rules
+// of the table so that the chart's fallbacks are used when doubling, splitting
+// or surrendering isn't allowed; moves the game still refuses fall back too.
+// Basic strategy never takes insurance.
+type BasicStrategyAI struct {
+	Strategy *Strategy
+	Rules    Rules
+	Unit     int
+
+	// hand is the hand last played and refused the number of times a move
+	// for it was refused.
+	hand    []deck.Card
+	refused int
+}
+
+func (ai *BasicStrategyAI) Bet() int {
+	ai.refused = 0
+	if ai.Unit <= 0 {
+		return 1
+	}
+	return ai.Unit
+}
+
+func (ai *BasicStrategyAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	return ai.play(ai.strategy().Action(hand, dealer), hand, dealer)
+}
+
+func (ai *BasicStrategyAI) strategy() *Strategy {
+	if ai.Strategy == nil {
+		ai.Strategy = DefaultStrategy(ai.Rules)
+	}
+	return ai.Strategy
+}
+
+// play returns the move for action, or its fallback once a move for the same
+// hand has been refused.
+func (ai *BasicStrategyAI) play(action Action, hand []deck.Card, dealer deck.Card) Move {
+	if !sameCards(ai.hand, hand) {
+		ai.hand = append(ai.hand[:0], hand...)
+		ai.refused = 0
+	}
+	move, fallback := ai.moves(action, hand, dealer)
+	switch ai.refused {
+	case 0:
+		return move
+	case 1:
+		return fallback
+	}
+	return MoveStand
+}
+
+// moves turns an action into the move to make and the move to fall back on
+// if the game refuses it.
+func (ai *BasicStrategyAI) moves(a Action, hand []deck.Card, dealer deck.Card) (Move, Move) {
+	first := len(hand) == 2
+	surrender := first && ai.Rules.Surrender != SurrenderNone
+	switch a {
+	case Stand:
+		return MoveStand, MoveStand
+	case DoubleOrHit, DoubleOrStand:
+		otherwise := Move(MoveHit)
+		if a == DoubleOrStand {
+			otherwise = MoveStand
+		}
+		if first && canDouble(ai.Rules, hand) {
+			return MoveDouble, otherwise
+		}
+		return otherwise, otherwise
+	case Split, SplitIfDAS:
+		up := value(dealer) - 2
+		if up < 0 {
+			up = 9
+		}
+		otherwise, _ := ai.moves(ai.strategy().total(hand, up), hand, dealer)
+		if a == SplitIfDAS && !ai.Rules.DoubleAfterSplit {
+			return otherwise, otherwise
+		}
+		return MoveSplit, otherwise
+	case SurrenderOrHit, SurrenderOrStand, SurrenderOrSplit:
+		otherwise, fallback := Move(MoveHit), Move(MoveHit)
+		switch a {
+		case SurrenderOrStand:
+			otherwise, fallback = MoveStand, MoveStand
+		case SurrenderOrSplit:
+			otherwise, fallback = ai.moves(Split, hand, dealer)
+		}
+		if surrender {
+			return MoveSurrender, otherwise
+		}
+		return otherwise, fallback
+	}
+	return MoveHit, MoveStand
+}
+
+// MoveError makes the next move for the same hand the chart's fallback.
+func (ai *BasicStrategyAI) MoveError(err error) {
+	ai.refused++
+}
+
+func (ai *BasicStrategyAI) Results(hand [][]deck.Card, dealer []deck.Card) {
+	ai.hand = ai.hand[:0]
+}
+
+// canDouble reports whether the rules allow doubling on the first two cards
+// of a hand.
+func canDouble(rules Rules, hand []deck.Card) bool {
+	score := Score(hand...)
+	switch rules.DoubleOn {
+	case DoubleNineToEleven:
+		return score >= 9 && score <= 11
+	case DoubleTenToEleven:
+		return score >= 10 && score <= 11
+	}
+	return true
+}
+
+func sameCards(a, b []deck.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
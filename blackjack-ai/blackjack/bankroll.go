@@ -0,0 +1,183 @@
+package blackjack
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Bankroll is a player's balance over a session: Start and the winnings of
+// every round played since.
+type Bankroll struct {
+	Start    float64   `json:"start"`
+	Winnings []float64 `json:"winnings"`
+}
+
+// Add records the winnings of a round.
+func (b *Bankroll) Add(winnings float64) {
+	b.Winnings = append(b.Winnings, winnings)
+}
+
+// Balance returns the balance after the last round.
+func (b *Bankroll) Balance() float64 {
+	balance := b.Start
+	for _, w := range b.Winnings {
+		balance += w
+	}
+	return balance
+}
+
+// History returns the balance after every round.
+func (b *Bankroll) History() []float64 {
+	ret := make([]float64, len(b.Winnings))
+	balance := b.Start
+	for i, w := range b.Winnings {
+		balance += w
+		ret[i] = balance
+	}
+	return ret
+}
+
+// MaxDrawdown returns the largest drop of the balance from a previous high.
+func (b *Bankroll) MaxDrawdown() float64 {
+	high, balance, drawdown := b.Start, b.Start, 0.0
+	for _, w := range b.Winnings {
+		balance += w
+		high = math.Max(high, balance)
+		drawdown = math.Max(drawdown, high-balance)
+	}
+	return drawdown
+}
+
+// LongestLosingStreak returns the largest number of rounds lost in a row.
+// Pushes neither extend nor break a streak.
+func (b *Bankroll) LongestLosingStreak() int {
+	streak, longest := 0, 0
+	for _, w := range b.Winnings {
+		switch {
+		case w < 0:
+			streak++
+		case w > 0:
+			streak = 0
+		}
+		if streak > longest {
+			longest = streak
+		}
+	}
+	return longest
+}
+
+// WriteCSV writes a row for every round with its winnings and the balance
+// after it, for plotting.
+func (b *Bankroll) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"round", "winnings", "balance"})
+	for i, balance := range b.History() {
+		cw.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(b.Winnings[i], 'f', -1, 64),
+			strconv.FormatFloat(balance, 'f', -1, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Bankroll returns the bankroll of a seat over every round played so far,
+// starting with start.
+func (g *Game) Bankroll(seat int, start float64) *Bankroll {
+	b := &Bankroll{Start: start}
+	for _, r := range g.rounds {
+		if r.Seat == seat {
+			b.Add(r.Winnings)
+		}
+	}
+	return b
+}
+
+// RiskOfRuin returns the probability of ever losing a bankroll playing as in
+// the simulation, from the usual approximation exp(-2 EV bankroll / variance).
+// It is 1 for a losing strategy.
+func (r SimResult) RiskOfRuin(bankroll float64) float64 {
+	if r.EV <= 0 {
+		return 1
+	}
+	if r.Variance == 0 {
+		return 0
+	}
+	return math.Exp(-2 * r.EV * bankroll / r.Variance)
+}
+
+// N0 returns the number of rounds after which the expected winnings, or
+// losses, equal one standard deviation of the results, i.e. the long run. It
+// is +Inf for a strategy that breaks even.
+func (r SimResult) N0() float64 {
+	if r.EV == 0 {
+		return math.Inf(1)
+	}
+	return r.Variance / (r.EV * r.EV)
+}
+
+// KellyUnit returns the betting unit that grows a bankroll the fastest, for
+// an AI that was simulated betting a unit of 1. It is 0 for a strategy that
+// doesn't win.
+func (r SimResult) KellyUnit(bankroll float64) float64 {
+	if r.EV <= 0 || r.Variance == 0 {
+		return 0
+	}
+	return bankroll * r.EV / r.Variance
+}
+
+// BankrollReport summarises the risk of playing a simulated strategy with a
+// bankroll. N0 is 0 when the strategy breaks even, and MaxDrawdown and
+// LongestLosingStreak are only known if the simulation kept its history.
+type BankrollReport struct {
+	Bankroll            float64 `json:"bankroll"`
+	Rounds              int     `json:"rounds"`
+	EV                  float64 `json:"ev"`
+	HouseEdge           float64 `json:"house_edge"`
+	StdDev              float64 `json:"std_dev"`
+	RiskOfRuin          float64 `json:"risk_of_ruin"`
+	N0                  float64 `json:"n0"`
+	KellyUnit           float64 `json:"kelly_unit"`
+	MaxDrawdown         float64 `json:"max_drawdown"`
+	LongestLosingStreak int     `json:"longest_losing_streak"`
+}
+
+// Report returns the bankroll analytics of the simulation for bankroll.
+func (r SimResult) Report(bankroll float64) BankrollReport {
+	ret := BankrollReport{
+		Bankroll:   bankroll,
+		Rounds:     r.Rounds,
+		EV:         r.EV,
+		HouseEdge:  r.HouseEdge,
+		StdDev:     r.StdDev,
+		RiskOfRuin: r.RiskOfRuin(bankroll),
+		N0:         r.N0(),
+		KellyUnit:  r.KellyUnit(bankroll),
+	}
+	if math.IsInf(ret.N0, 0) {
+		ret.N0 = 0
+	}
+	if r.Bankroll != nil {
+		ret.MaxDrawdown = r.Bankroll.MaxDrawdown()
+		ret.LongestLosingStreak = r.Bankroll.LongestLosingStreak()
+	}
+	return ret
+}
+
+// WriteCSV writes the report as a header and a single row.
+func (r BankrollReport) WriteCSV(w io.Writer) error {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"bankroll", "rounds", "ev", "house_edge", "std_dev", "risk_of_ruin", "n0", "kelly_unit", "max_drawdown", "longest_losing_streak"})
+	cw.Write([]string{
+		f(r.Bankroll), strconv.Itoa(r.Rounds), f(r.EV), f(r.HouseEdge), f(r.StdDev),
+		f(r.RiskOfRuin), f(r.N0), f(r.KellyUnit), f(r.MaxDrawdown), strconv.Itoa(r.LongestLosingStreak),
+	})
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,100 @@
+package blackjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBankroll(t *testing.T) {
+	b := &Bankroll{Start: 10}
+	for _, w := range []float64{1, -1, -2, 0, -1, 3, -1.5, 4} {
+		b.Add(w)
+	}
+	if b.Balance() != 12.5 {
+		t.Errorf("expected a balance of 12.5, got: %v", b.Balance())
+	}
+	if h := b.History(); len(h) != 8 || h[0] != 11 || h[4] != 7 {
+		t.Errorf("expected the balance after every round, got: %v", h)
+	}
+	if b.MaxDrawdown() != 4 {
+		t.Errorf("expected a drawdown of 4, from 11 to 7, got: %v", b.MaxDrawdown())
+	}
+	if b.LongestLosingStreak() != 3 {
+		t.Errorf("expected a losing streak of 3, got: %d", b.LongestLosingStreak())
+	}
+	var buf bytes.Buffer
+	if err := b.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 9 || lines[1] != "1,1,11" || lines[8] != "8,4,12.5" {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestGameBankroll(t *testing.T) {
+	g := New(Options{Hands: 20})
+	if _, _, err := g.PlayTable(&BasicStrategyAI{}, &BasicStrategyAI{}); err != nil {
+		t.Fatal(err)
+	}
+	balances := []float64{g.seats[0].balance, g.seats[1].balance}
+	for seat, balance := range balances {
+		b := g.Bankroll(seat, 100)
+		if len(b.Winnings) != 20 || b.Balance() != 100+balance {
+			t.Errorf("seat %d: expected 20 rounds ending at %v, got %d ending at %v", seat, 100+balance, len(b.Winnings), b.Balance())
+		}
+	}
+}
+
+func TestRiskAnalytics(t *testing.T) {
+	r := SimResult{EV: 0.02, Variance: 1.3}
+	if n0 := r.N0(); math.Abs(n0-3250) > 1e-9 {
+		t.Errorf("expected N0 of 3250, got: %v", n0)
+	}
+	if k := r.KellyUnit(1000); math.Abs(k-1000*0.02/1.3) > 1e-9 {
+		t.Errorf("expected a Kelly unit of %v, got: %v", 1000*0.02/1.3, k)
+	}
+	if ror := r.RiskOfRuin(100); math.Abs(ror-math.Exp(-4/1.3)) > 1e-9 {
+		t.Errorf("expected a risk of ruin of %v, got: %v", math.Exp(-4/1.3), ror)
+	}
+	if r.RiskOfRuin(1000) >= r.RiskOfRuin(100) {
+		t.Errorf("expected a larger bankroll to be safer")
+	}
+	losing := SimResult{EV: -0.01, Variance: 1.3}
+	if losing.RiskOfRuin(1e6) != 1 || losing.KellyUnit(1000) != 0 {
+		t.Errorf("expected a losing strategy to be ruined and bet nothing")
+	}
+}
+
+func TestSimulateReport(t *testing.T) {
+	res, err := Simulate(SimOptions{Rounds: 2000, Workers: 2, Seed: 1, History: true, Bankroll: 100}, func() AI {
+		return &BasicStrategyAI{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Bankroll == nil || len(res.Bankroll.Winnings) != 2000 {
+		t.Fatalf("expected the history of 2000 rounds")
+	}
+	if math.Abs(res.Bankroll.Balance()-(100+res.Winnings)) > 1e-9 {
+		t.Errorf("expected the history to add up to the winnings")
+	}
+	report := res.Report(100)
+	if report.MaxDrawdown <= 0 || report.LongestLosingStreak <= 0 {
+		t.Errorf("expected a drawdown and a losing streak over 2000 rounds, got: %+v", report)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"risk_of_ruin":`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("expected a header and a row, got: %q (%v)", buf.String(), err)
+	}
+}
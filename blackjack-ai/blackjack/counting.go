@@ -0,0 +1,347 @@
+package blackjack
+
+import (
+	"math"
+	"strconv"
+
+	"deck"
+)
+
+// CountSystem is a card counting system. Tags holds the value added to the
+// running count for every card seen, indexed by the card's blackjack value
+// with the Ace at 1. Systems whose tags don't add up to 0 over a deck are
+// unbalanced; they start the running count at InitialCount for every deck in
+// the shoe but one.
+//
+// Level is roughly how much larger the system's true count is than the
+// Hi-Lo's, which index plays are calibrated on.
+type CountSystem struct {
+	Name         string
+	Tags         [11]int
+	InitialCount int
+	Level        float64
+}
+
+var (
+	// HiLo counts 2 to 6 as +1 and tens and Aces as -1.
+	HiLo = CountSystem{
+		Name:  "Hi-Lo",
+		Tags:  [11]int{0, -1, 1, 1, 1, 1, 1, 0, 0, 0, -1},
+		Level: 1,
+	}
+	// KO, Knock-Out, is the Hi-Lo with the 7 counted as +1 as well. It is
+	// unbalanced and starts at 4 - 4 per deck, so that 4 is the key count.
+	KO = CountSystem{
+		Name:         "KO",
+		Tags:         [11]int{0, -1, 1, 1, 1, 1, 1, 1, 0, 0, -1},
+		InitialCount: -4,
+		Level:        1,
+	}
+	// OmegaII is a level 2 count that ignores the Ace: 4 to 6 count +2, 2, 3
+	// and 7 count +1, 9 counts -1 and tens count -2.
+	OmegaII = CountSystem{
+		Name:  "Omega II",
+		Tags:  [11]int{0, 0, 1, 1, 2, 2, 2, 1, 0, -1, -2},
+		Level: 2,
+	}
+)
+
+// Tag returns the count of a single card.
+func (cs CountSystem) Tag(c deck.Card) int {
+	if c.Suit == deck.Joker {
+		return 0
+	}
+	return cs.Tags[value(c)]
+}
+
+// initial returns the running count of a fresh shoe of decks.
+func (cs CountSystem) initial(decks int) int {
+	return cs.InitialCount * (decks - 1)
+}
+
+// imbalance returns what the tags of a full deck add up to.
+func (cs CountSystem) imbalance() int {
+	sum := 0
+	for v := 1; v <= 10; v++ {
+		n := 4
+		if v == 10 {
+			n = 16
+		}
+		sum += n * cs.Tags[v]
+	}
+	return sum
+}
+
+// Deviation is an index play: with Hand, e.g. "hard 16" or "pair T", against
+// the dealer's up card, 2 to 11 for an Ace, Action is played instead of the
+// strategy chart once the true count is at least Index. With Below set it is
+// played while the true count is below Index instead.
+type Deviation struct {
+	Hand   string
+	Dealer int
+	Index  float64
+	Below  bool
+	Action Action
+}
+
+var (
+	// Illustrious18 are the 18 most valuable index plays for the Hi-Lo, less
+	// insurance which is CountingAI.InsuranceIndex.
+	Illustrious18 = []Deviation{
+		{Hand: "hard 16", Dealer: 10, Index: 0, Action: SurrenderOrStand},
+		{Hand: "hard 15", Dealer: 10, Index: 4, Action: SurrenderOrStand},
+		{Hand: "pair T", Dealer: 5, Index: 5, Action: Split},
+		{Hand: "pair T", Dealer: 6, Index: 4, Action: Split},
+		{Hand: "hard 10", Dealer: 10, Index: 4, Action: DoubleOrHit},
+		{Hand: "hard 12", Dealer: 3, Index: 2, Action: Stand},
+		{Hand: "hard 12", Dealer: 2, Index: 3, Action: Stand},
+		{Hand: "hard 11", Dealer: 11, Index: 1, Action: DoubleOrHit},
+		{Hand: "hard 9", Dealer: 2, Index: 1, Action: DoubleOrHit},
+		{Hand: "hard 10", Dealer: 11, Index: 4, Action: DoubleOrHit},
+		{Hand: "hard 9", Dealer: 7, Index: 3, Action: DoubleOrHit},
+		{Hand: "hard 16", Dealer: 9, Index: 5, Action: SurrenderOrStand},
+		{Hand: "hard 13", Dealer: 2, Index: -1, Below: true, Action: Hit},
+		{Hand: "hard 12", Dealer: 4, Index: 0, Below: true, Action: Hit},
+		{Hand: "hard 12", Dealer: 5, Index: -2, Below: true, Action: Hit},
+		{Hand: "hard 12", Dealer: 6, Index: -1, Below: true, Action: Hit},
+		{Hand: "hard 13", Dealer: 3, Index: -2, Below: true, Action: Hit},
+	}
+	// Fab4 are the four most valuable surrender index plays for the Hi-Lo.
+	// They only make a difference where surrendering is allowed.
+	Fab4 = []Deviation{
+		{Hand: "hard 14", Dealer: 10, Index: 3, Action: SurrenderOrHit},
+		{Hand: "hard 15", Dealer: 10, Index: 0, Below: true, Action: Hit},
+		{Hand: "hard 15", Dealer: 9, Index: 2, Action: SurrenderOrHit},
+		{Hand: "hard 15", Dealer: 11, Index: 1, Action: SurrenderOrHit},
+	}
+)
+
+// BetStep is a step of a BetRamp: Units are bet from a true count of
+// TrueCount upwards.
+type BetStep struct {
+	TrueCount float64
+	Units     int
+}
+
+// BetRamp sizes bets by the true count. Steps are in increasing order of
+// true count; below the first step a single unit is bet.
+type BetRamp []BetStep
+
+// DefaultBetRamp spreads bets from 1 to 8 units between a true count of 2
+// and 5.
+var DefaultBetRamp = BetRamp{{2, 2}, {3, 4}, {4, 6}, {5, 8}}
+
+// Units returns the number of units to bet at a true count.
+func (r BetRamp) Units(tc float64) int {
+	units := 1
+	for _, step := range r {
+		if tc < step.TrueCount {
+			break
+		}
+		units = step.Units
+	}
+	return units
+}
+
+// CountingAI plays basic strategy while counting cards with System. It sizes
+// its bets with Ramp, plays Deviations, checked in order, and takes
+// insurance from a true count of InsuranceIndex. Deviations and the insurance
+// index are in Hi-Lo units and scaled by the system's level.
+//
+// The count is kept from the hands shown at the end of every round, those of
+// the other seats included, and reset whenever the shoe is reshuffled. The
+// true count divides it by the number of decks, of 52 cards, left in the
+// shoe.
+type CountingAI struct {
+	BasicStrategyAI
+	System         CountSystem
+	Ramp           BetRamp
+	Deviations     []Deviation
+	InsuranceIndex float64
+
+	running int
+	seen    int
+	counted bool
+}
+
+// NewCountingAI returns an AI counting with system for the rules, using the
+// default bet ramp, the Illustrious 18 and Fab 4 and taking insurance from a
+// true count of 3.
+func NewCountingAI(system CountSystem, rules Rules) *CountingAI {
+	validateRules(&rules)
+	var deviations []Deviation
+	deviations = append(deviations, Illustrious18...)
+	deviations = append(deviations, Fab4...)
+	return &CountingAI{
+		BasicStrategyAI: BasicStrategyAI{Rules: rules},
+		System:          system,
+		Ramp:            DefaultBetRamp,
+		Deviations:      deviations,
+		InsuranceIndex:  3,
+	}
+}
+
+// HiLoAI returns an AI counting with the Hi-Lo. See NewCountingAI.
+func HiLoAI(rules Rules) *CountingAI {
+	return NewCountingAI(HiLo, rules)
+}
+
+// KOAI returns an AI counting with the KO. See NewCountingAI.
+func KOAI(rules Rules) *CountingAI {
+	return NewCountingAI(KO, rules)
+}
+
+// OmegaIIAI returns an AI counting with the Omega II. See NewCountingAI.
+func OmegaIIAI(rules Rules) *CountingAI {
+	return NewCountingAI(OmegaII, rules)
+}
+
+// Shuffled resets the count.
+func (ai *CountingAI) Shuffled() {
+	ai.running = ai.System.initial(ai.decks())
+	ai.seen = 0
+	ai.counted = true
+}
+
+func (ai *CountingAI) decks() int {
+	if ai.Rules.Decks <= 0 {
+		return 3
+	}
+	return ai.Rules.Decks
+}
+
+func (ai *CountingAI) count(cards ...deck.Card) {
+	if !ai.counted {
+		ai.Shuffled()
+	}
+	for _, c := range cards {
+		ai.running += ai.System.Tag(c)
+		ai.seen++
+	}
+}
+
+// RunningCount returns the count of every card seen since the last
+// reshuffle.
+func (ai *CountingAI) RunningCount() int {
+	if !ai.counted {
+		ai.Shuffled()
+	}
+	return ai.running
+}
+
+// TrueCount returns the running count per deck left in the shoe. For an
+// unbalanced system the count expected from the cards seen so far is taken
+// off first, so that it compares with the true count of a balanced one.
+func (ai *CountingAI) TrueCount() float64 {
+	return ai.trueCount()
+}
+
+// trueCount returns the true count as if cards had been seen as well.
+func (ai *CountingAI) trueCount(cards ...deck.Card) float64 {
+	running, seen := ai.RunningCount(), ai.seen
+	for _, c := range cards {
+		running += ai.System.Tag(c)
+		seen++
+	}
+	decks := float64(ai.decks())
+	expected := float64(ai.System.initial(ai.decks())) + float64(ai.System.imbalance())*float64(seen)/52
+	left := math.Max(decks-float64(seen)/52, 0.5)
+	return (float64(running) - expected) / left
+}
+
+func (ai *CountingAI) Bet() int {
+	unit := ai.BasicStrategyAI.Bet()
+	return unit * ai.Ramp.Units(ai.TrueCount()/ai.level())
+}
+
+func (ai *CountingAI) level() float64 {
+	if ai.System.Level <= 0 {
+		return 1
+	}
+	return ai.System.Level
+}
+
+func (ai *CountingAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	tc := ai.trueCount(append(hand[:len(hand):len(hand)], dealer)...) / ai.level()
+	return ai.play(ai.action(hand, dealer, tc), hand, dealer)
+}
+
+// action returns the action of the first deviation that applies to hand at
+// the true count tc, or that of the strategy chart.
+func (ai *CountingAI) action(hand []deck.Card, dealer deck.Card, tc float64) Action {
+	basic := ai.strategy().Action(hand, dealer)
+	up := value(dealer)
+	if up == 1 {
+		up = 11
+	}
+	keys := []string{handKey(hand)}
+	if len(hand) == 2 && value(hand[0]) == value(hand[1]) {
+		switch basic {
+		case Split, SurrenderOrSplit:
+		case SplitIfDAS:
+			if !ai.Rules.DoubleAfterSplit {
+				keys = append(keys, totalKey(hand))
+			}
+		default:
+			keys = append(keys, totalKey(hand))
+		}
+	}
+	for _, key := range keys {
+		for _, d := range ai.Deviations {
+			if d.Hand != key || d.Dealer != up {
+				continue
+			}
+			if (tc >= d.Index) != d.Below {
+				return d.Action
+			}
+		}
+	}
+	return basic
+}
+
+// handKey describes a hand as a strategy chart does, e.g. "pair 8".
+func handKey(hand []deck.Card) string {
+	if len(hand) == 2 && value(hand[0]) == value(hand[1]) {
+		switch v := value(hand[0]); v {
+		case 1:
+			return "pair A"
+		case 10:
+			return "pair T"
+		default:
+			return "pair " + strconv.Itoa(v)
+		}
+	}
+	return totalKey(hand)
+}
+
+// totalKey describes a hand by its total, e.g. "soft 18" or "hard 16".
+func totalKey(hand []deck.Card) string {
+	kind := "hard "
+	if Soft(hand...) {
+		kind = "soft "
+	}
+	return kind + strconv.Itoa(Score(hand...))
+}
+
+// Insurance takes insurance, or even money, from a true count of
+// InsuranceIndex.
+func (ai *CountingAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	tc := ai.trueCount(append(hand[:len(hand):len(hand)], dealer)...) / ai.level()
+	return tc >= ai.InsuranceIndex
+}
+
+// Results counts the cards of the round.
+func (ai *CountingAI) Results(hand [][]deck.Card, dealer []deck.Card) {
+	ai.BasicStrategyAI.Results(hand, dealer)
+	for _, h := range hand {
+		ai.count(h...)
+	}
+	ai.count(dealer...)
+}
+
+// Watch counts the cards of the other seats.
+func (ai *CountingAI) Watch(hands [][]deck.Card, dealer []deck.Card) {
+	for _, h := range hands {
+		ai.count(h...)
+	}
+}
@@ -0,0 +1,112 @@
+package blackjack
+
+import (
+	"math"
+	"testing"
+
+	"deck"
+)
+
+func TestCountSystemsBalance(t *testing.T) {
+	tests := []struct {
+		system CountSystem
+		want   int
+	}{
+		{HiLo, 0},
+		{KO, 4},
+		{OmegaII, 0},
+	}
+	for _, tt := range tests {
+		sum := 0
+		for _, c := range deck.New() {
+			sum += tt.system.Tag(c)
+		}
+		if sum != tt.want {
+			t.Errorf("%s: expected a deck to count %d, got: %d", tt.system.Name, tt.want, sum)
+		}
+	}
+}
+
+func TestBetRamp(t *testing.T) {
+	for tc, want := range map[float64]int{-3: 1, 1.9: 1, 2: 2, 3.5: 4, 4: 6, 12: 8} {
+		if got := DefaultBetRamp.Units(tc); got != want {
+			t.Errorf("true count %v: expected %d units, got: %d", tc, want, got)
+		}
+	}
+}
+
+func TestCountingAICounts(t *testing.T) {
+	g := New(Options{Rules: Rules{Decks: 1}})
+	g.shoe = stacked(t, "2s 3s Kh 5d 6c Ad")
+	ai := HiLoAI(g.rules)
+	g.sit(ai, &scriptedAI{})
+	bet(&g)
+	deal(&g)
+	peek(&g)
+	playerTurn(&g)
+	finish(&g)
+	// 2 3 5 6 count +4 and the K and A -2
+	if ai.RunningCount() != 2 {
+		t.Errorf("expected a running count of 2, got: %d", ai.RunningCount())
+	}
+	if tc := ai.TrueCount(); math.Abs(tc-2/(1-6.0/52)) > 1e-9 {
+		t.Errorf("expected a true count of %v, got: %v", 2/(1-6.0/52), tc)
+	}
+	reshuffle(&g)
+	if ai.RunningCount() != 0 || ai.TrueCount() != 0 {
+		t.Errorf("expected the reshuffle to reset the count")
+	}
+
+	ko := KOAI(Rules{Decks: 6})
+	if ko.RunningCount() != -20 || ko.TrueCount() != 0 {
+		t.Errorf("expected KO to start at -20 with a true count of 0, got: %d and %v", ko.RunningCount(), ko.TrueCount())
+	}
+}
+
+func TestDeviations(t *testing.T) {
+	ai := HiLoAI(VegasStrip)
+	tests := []struct {
+		hand   string
+		dealer string
+		tc     float64
+		want   Action
+	}{
+		{"Ts 6d", "Kh", -1, SurrenderOrHit},
+		{"Ts 6d", "Kh", 0, SurrenderOrStand},
+		{"Ts 2d", "3h", 1, Hit},
+		{"Ts 2d", "3h", 2, Stand},
+		{"Ts 3d", "2h", -2, Hit},
+		{"Ts 3d", "2h", -1, Stand},
+		{"Ts Kd", "6h", 3, Stand},
+		{"Ts Kd", "6h", 4, Split},
+		{"6s 6d", "2h", 5, SplitIfDAS},
+		{"Ts 5d", "Ah", 1, SurrenderOrHit},
+		{"Ts 5d", "Ah", 0, Hit},
+	}
+	for _, tt := range tests {
+		hand, _ := deck.ParseHand(tt.hand)
+		dealer, _ := deck.Parse(tt.dealer)
+		if got := ai.action(hand, dealer, tt.tc); got != tt.want {
+			t.Errorf("%s against %s at %v: expected %s, got: %s", tt.hand, tt.dealer, tt.tc, tt.want, got)
+		}
+	}
+}
+
+func TestCountingAIPlays(t *testing.T) {
+	for _, system := range []CountSystem{HiLo, KO, OmegaII} {
+		g := New(Options{Rules: VegasStrip, Hands: 1000})
+		ai := NewCountingAI(system, VegasStrip)
+		if _, _, err := g.PlayTable(ai, &BasicStrategyAI{Rules: VegasStrip}); err != nil {
+			t.Fatal(err)
+		}
+		bets := make(map[int]bool)
+		for _, r := range g.Rounds() {
+			if r.Seat == 0 {
+				bets[r.Hands[0].Bet] = true
+			}
+		}
+		if len(bets) < 2 {
+			t.Errorf("%s: expected the bets to follow the count, got: %v", system.Name, bets)
+		}
+	}
+}
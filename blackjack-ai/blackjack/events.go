@@ -0,0 +1,195 @@
+package blackjack
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"deck"
+)
+
+// EventType is the kind of an Event.
+type EventType string
+
+const (
+	// EventShuffled is sent whenever the shoe is shuffled, and before the
+	// first round, with the Commitment of the seed of the shuffle. The seed
+	// itself would give the whole shoe away, so it is only kept by Capture.
+	EventShuffled EventType = "shuffled"
+	// EventRoundStarted is sent before the bets of every round.
+	EventRoundStarted EventType = "round_started"
+	// EventBetPlaced is sent for the bet of every seat.
+	EventBetPlaced EventType = "bet_placed"
+	// EventCardDealt is sent for every card dealt. The dealer's hole card is
+	// Hidden, without its Card, which is only sent with EventDealerRevealed.
+	EventCardDealt EventType = "card_dealt"
+	// EventInsurance is sent when a seat takes insurance, Amount, or even
+	// money.
+	EventInsurance EventType = "insurance"
+	// EventMoveTaken is sent for every move made, by the players and the
+	// dealer alike. Early is set for early surrenders, made before the
+	// dealer checks for a blackjack.
+	EventMoveTaken EventType = "move_taken"
+	// EventMoveRefused is sent for every illegal move, with the Error.
+	EventMoveRefused EventType = "move_refused"
+	// EventDealerRevealed is sent with the dealer's Cards once the hole card
+	// is turned over.
+	EventDealerRevealed EventType = "dealer_revealed"
+	// EventRoundSettled is sent for every seat at the end of a round, with
+	// its Result and winnings as the Amount.
+	EventRoundSettled EventType = "round_settled"
+)
+
+// DealerSeat is the Seat of events about the dealer.
+const DealerSeat = -1
+
+// Event is something that happened in a Game. Round counts the rounds from
+// 1, Seat is the seat, or DealerSeat, the event is about and Hand the index
+// of the seat's hand. The other fields are set depending on the Type.
+//
+// In JSON, Card and Cards are written with their identity in the shoe, as
+// deck.Card.ID does, so that a history can be audited card by card.
+type Event struct {
+	Type       EventType   `json:"type"`
+	Round      int         `json:"round"`
+	Seat       int         `json:"seat"`
+	Hand       int         `json:"hand"`
+	Card       *deck.Card  `json:"card,omitempty"`
+	Hidden     bool        `json:"hidden,omitempty"`
+	Cards      []deck.Card `json:"cards,omitempty"`
+	Bet        int         `json:"bet,omitempty"`
+	Move       string      `json:"move,omitempty"`
+	Early      bool        `json:"early,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Amount     float64     `json:"amount,omitempty"`
+	Commitment string      `json:"commitment,omitempty"`
+	Result     *Round      `json:"result,omitempty"`
+}
+
+// eventJSON is an Event as written to JSON.
+type eventJSON struct {
+	event
+	Card  *cardID  `json:"card,omitempty"`
+	Cards []cardID `json:"cards,omitempty"`
+}
+
+type event Event
+
+// cardID is a card written to JSON with its identity.
+type cardID deck.Card
+
+func (c cardID) MarshalText() ([]byte, error) {
+	if _, err := deck.Card(c).MarshalText(); err != nil {
+		return nil, err
+	}
+	return []byte(deck.Card(c).ID()), nil
+}
+
+// UnmarshalText also accepts cards without their identity.
+func (c *cardID) UnmarshalText(text []byte) error {
+	parse := deck.ParseID
+	if !strings.Contains(string(text), "#") {
+		parse = deck.Parse
+	}
+	card, err := parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = cardID(card)
+	return nil
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	j := eventJSON{event: event(e), Card: (*cardID)(e.Card)}
+	for _, c := range e.Cards {
+		j.Cards = append(j.Cards, cardID(c))
+	}
+	return json.Marshal(j)
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var j eventJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*e = Event(j.event)
+	e.Card = (*deck.Card)(j.Card)
+	e.Cards = nil
+	for _, c := range j.Cards {
+		e.Cards = append(e.Cards, deck.Card(c))
+	}
+	return nil
+}
+
+// Observer is told about every Event of the games it observes.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc lets an ordinary function be used as an Observer.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Observe registers observers to be told about every event of the game from
+// now on, in the order they were registered.
+func (g *Game) Observe(obs ...Observer) {
+	g.observers = append(g.observers, obs...)
+}
+
+// emit sends e, for the current round, to the game's observers.
+func emit(g *Game, e Event) {
+	if len(g.observers) == 0 {
+		return
+	}
+	e.Round = g.round
+	for _, o := range g.observers {
+		o.Observe(e)
+	}
+}
+
+// HistoryRecorder is an Observer that writes every event as a line of JSON,
+// to be read back with ReadHistory.
+type HistoryRecorder struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewHistoryRecorder returns a HistoryRecorder writing to w.
+func NewHistoryRecorder(w io.Writer) *HistoryRecorder {
+	return &HistoryRecorder{enc: json.NewEncoder(w)}
+}
+
+func (r *HistoryRecorder) Observe(e Event) {
+	if r.err != nil {
+		return
+	}
+	r.err = r.enc.Encode(e)
+}
+
+// Err returns the first error writing the history, after which nothing more
+// is written.
+func (r *HistoryRecorder) Err() error {
+	return r.err
+}
+
+// ReadHistory reads the events written by a HistoryRecorder.
+func ReadHistory(r io.Reader) ([]Event, error) {
+	var ret []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return ret, err
+		}
+		ret = append(ret, e)
+	}
+	return ret, scanner.Err()
+}
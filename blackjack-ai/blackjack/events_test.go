@@ -0,0 +1,102 @@
+package blackjack
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	g := New(Options{})
+	g.shoe = stacked(t, "Ts 6s 7h Td 5c")
+	g.sit(&scriptedAI{})
+	var events []Event
+	g.Observe(ObserverFunc(func(e Event) {
+		events = append(events, e)
+	}))
+	if err := playRound(&g); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	want := []EventType{
+		EventShuffled, EventRoundStarted, EventBetPlaced,
+		EventCardDealt, EventCardDealt, EventCardDealt, EventCardDealt,
+		EventMoveTaken, EventDealerRevealed, EventMoveTaken, EventCardDealt, EventMoveTaken,
+		EventRoundSettled,
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected events %v, got: %v", want, types)
+	}
+	if e := events[6]; e.Seat != DealerSeat || !e.Hidden || e.Card != nil {
+		t.Errorf("expected the hole card to be hidden, got: %+v", e)
+	}
+	if e := events[8]; len(e.Cards) != 2 || e.Cards[1].Short() != "Td" {
+		t.Errorf("expected the hole card to be revealed, got: %+v", e)
+	}
+	if e := events[7]; e.Seat != 0 || e.Move != "stand" {
+		t.Errorf("expected the player to stand, got: %+v", e)
+	}
+	if e := events[9]; e.Seat != DealerSeat || e.Move != "hit" {
+		t.Errorf("expected the dealer to hit, got: %+v", e)
+	}
+	if e := events[12]; e.Round != 1 || e.Amount != -1 || e.Result == nil || len(e.Result.Dealer) != 3 {
+		t.Errorf("expected the player to lose the round to 21, got: %+v", e)
+	}
+}
+
+func TestMoveRefusedEvent(t *testing.T) {
+	g := newRound(t, Options{}, "6s Ts 5h 7d 9c")
+	var refused []Event
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Type == EventMoveRefused {
+			refused = append(refused, e)
+		}
+	}))
+	g.seats[0].ai = &scriptedAI{moves: []Move{MoveSplit}}
+	playerTurn(g)
+	if len(refused) != 1 || refused[0].Error == "" {
+		t.Errorf("expected the split to be refused, got: %v", refused)
+	}
+}
+
+func TestHistoryRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewHistoryRecorder(&buf)
+	var events []Event
+	g := New(Options{Rules: AtlanticCity, Hands: 20})
+	g.Observe(rec, ObserverFunc(func(e Event) {
+		events = append(events, e)
+	}))
+	g.PlayTable(&BasicStrategyAI{Rules: AtlanticCity}, &BasicStrategyAI{Rules: AtlanticCity})
+	if rec.Err() != nil {
+		t.Fatal(rec.Err())
+	}
+	read, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(events) {
+		t.Fatalf("expected %d events, got: %d", len(events), len(read))
+	}
+	for i, e := range read {
+		if e.Type != events[i].Type || e.Round != events[i].Round || e.Seat != events[i].Seat || e.Move != events[i].Move {
+			t.Fatalf("event %d: expected %+v, got: %+v", i, events[i], e)
+		}
+		if (e.Card == nil) != (events[i].Card == nil) || (e.Card != nil && *e.Card != *events[i].Card) {
+			t.Errorf("event %d: expected %v, got: %v", i, events[i].Card, e.Card)
+		}
+		if !reflect.DeepEqual(e.Cards, events[i].Cards) {
+			t.Errorf("event %d: expected %v, got: %v", i, events[i].Cards, e.Cards)
+		}
+		if e.Result != nil && e.Result.Winnings != events[i].Result.Winnings {
+			t.Errorf("event %d: expected winnings of %v, got: %v", i, events[i].Result.Winnings, e.Result.Winnings)
+		}
+	}
+	if last := read[len(read)-1]; last.Type != EventRoundSettled || last.Round != 20 {
+		t.Errorf("expected the history to end with round 20 being settled, got: %+v", last)
+	}
+}
@@ -1,7 +1,9 @@
 package blackjack
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"deck"
@@ -15,144 +17,802 @@ const (
 
 type state int8
 
+// Options configures a Game. Rules are the table rules, e.g. VegasStrip.
+// Hands is the number of rounds Play plays, and the shoe is reshuffled once
+// Penetration of it, 0.75 by default, has been dealt; it must be less than 1.
+// A shoe that runs out in the middle of a round is reshuffled without the
+// cards on the table. Deck selects the cards every deck in the shoe is made
+// of, e.g. deck.Spanish21; the zero value is a standard deck. Shuffler
+// shuffles the shoe, deck.CryptoShuffler() if unset; use deck.SeededShuffler
+// for games that can be repeated.
+//
+// A game is silent unless Output is set, in which case Play and PlayTable
+// write a line to it for every seat at the end of every round.
 type Options struct {
-	Decks           int
-	Hands           int
-	BlackJackPayout float64
+	Rules
+	Hands       int
+	Penetration float64
+	Deck        deck.Spec
+	Shuffler    deck.Shuffler
+	Output      io.Writer
 }
 
 func validateOptions(opts *Options) {
-	if opts.Decks <= 0 {
-		opts.Decks = 3
-	}
+	validateRules(&opts.Rules)
 	if opts.Hands <= 0 {
 		opts.Hands = 2
 	}
-	if opts.BlackJackPayout <= 0 {
-		opts.BlackJackPayout = 1.5
+	if opts.Penetration <= 0 || opts.Penetration >= 1 {
+		opts.Penetration = 0.75
 	}
 }
 
 func New(opts Options) Game {
 	validateOptions(&opts)
 	return Game{
-		state:           statePlayerTurn,
-		dealerAI:        dealerAI{},
-		balance:         0,
-		nDecks:          opts.Decks,
-		nHands:          opts.Hands,
-		blackJackPayout: opts.BlackJackPayout,
+		shoe: deck.NewShoe(deck.ShoeOptions{
+			Decks:       opts.Decks,
+			Penetration: opts.Penetration,
+			Cards:       []func([]deck.Card) []deck.Card{deck.FromSpec(opts.Deck)},
+			Shuffler:    opts.Shuffler,
+		}),
+		state:    stateHandOver,
+		dealerAI: dealerAI{hitSoft17: !opts.DealerStandsSoft17},
+		rules:    opts.Rules,
+		nHands:   opts.Hands,
+		opts:     opts,
 	}
 }
 
 type Game struct {
 	// unexported fields
-	deck            []deck.Card
-	state           state
-	player          []deck.Card
-	dealer          []deck.Card
-	dealerAI        AI
-	balance         int
-	nDecks          int
-	nHands          int
-	blackJackPayout float64
-	playerBet       int
-}
-
-func (g *Game) currentHand() *[]deck.Card {
+	shoe     *deck.Shoe
+	state    state
+	seats    []*seat
+	seatIdx  int
+	dealer   []deck.Card
+	dealerAI AI
+	rules    Rules
+	nHands   int
+	rounds   []Round
+	opts     Options
+
+	// round counts the rounds played and observers are told about every
+	// event of the game.
+	round     int
+	observers []Observer
+
+	// early is set while a player decides whether to surrender early.
+	early bool
+}
+
+// MaxSeats is the number of seats at a blackjack table.
+const MaxSeats = 7
+
+// ErrSeats is returned by PlayTable when there are no AIs to seat, or more
+// than MaxSeats of them.
+var ErrSeats = errors.New("blackjack: a table seats between 1 and 7 players")
+
+// seat is a place at the table, played by its own AI with its own bets and
+// balance. Seats play in order, all from the same shoe.
+type seat struct {
+	ai        AI
+	hands     []hand
+	handIdx   int
+	bet       int
+	insurance float64
+	balance   float64
+
+	// pending holds the move chosen instead of an early surrender, to be
+	// made after the peek.
+	pending Move
+}
+
+// hand is one of a player's hands. A player starts each round with a single
+// hand and gets another one, with its own bet, every time they split.
+type hand struct {
+	cards       []deck.Card
+	bet         int
+	splitAces   bool
+	evenMoney   bool
+	surrendered bool
+}
+
+// Round records the outcome of a single round for one seat. Insurance is the
+// size of the insurance bet, 0 if none was taken, and InsuranceWinnings what
+// it won or lost. Winnings is the net result of the round, insurance
+// included.
+type Round struct {
+	Seat              int
+	Hands             []HandResult
+	Dealer            []deck.Card
+	Insurance         float64
+	InsuranceWinnings float64
+	Winnings          float64
+}
+
+// HandResult records how one of the player's hands was settled. EvenMoney
+// is set when the player took even money on a blackjack and Surrendered when
+// they gave up the hand.
+type HandResult struct {
+	Cards       []deck.Card
+	Bet         int
+	EvenMoney   bool
+	Surrendered bool
+	Winnings    float64
+}
+
+// sit seats the AIs at the table in order, each with a balance of 0.
+func (g *Game) sit(ais ...AI) {
+	g.seats = make([]*seat, len(ais))
+	for i, ai := range ais {
+		g.seats[i] = &seat{ai: ai}
+	}
+	g.seatIdx = 0
+}
+
+// seat returns the seat whose turn it is.
+func (g *Game) seat() *seat {
+	return g.seats[g.seatIdx]
+}
+
+// ErrInvalidState is returned when the game is asked to do something its
+// current state doesn't allow, such as making a move once a round is over.
+var ErrInvalidState = errors.New("blackjack: it isn't currently anyone's turn")
+
+func (g *Game) currentHand() (*[]deck.Card, error) {
 	switch g.state {
 	case statePlayerTurn:
-		return &g.player
+		if g.seatIdx < 0 || g.seatIdx >= len(g.seats) {
+			return nil, ErrInvalidState
+		}
+		s := g.seat()
+		return &s.hands[s.handIdx].cards, nil
 	case stateDealerTurn:
-		return &g.dealer
-	default:
-		panic("it isn't currently any player's turn")
+		return &g.dealer, nil
 	}
+	return nil, ErrInvalidState
 }
 
-func bet(g *Game, ai AI) {
-	g.playerBet = ai.Bet()
+// ErrInvalidBet is returned, wrapped with the seat and bet, when an AI bets
+// nothing or less.
+var ErrInvalidBet = errors.New("blackjack: bets must be positive")
+
+func bet(g *Game) error {
+	for i, s := range g.seats {
+		s.bet = s.ai.Bet()
+		if s.bet <= 0 {
+			return fmt.Errorf("%w: seat %d bet %d", ErrInvalidBet, i, s.bet)
+		}
+		emit(g, Event{Type: EventBetPlaced, Seat: i, Bet: s.bet})
+	}
+	return nil
 }
 
+// deal deals two cards to every seat, in seat order, and two to the dealer,
+// or only one without a hole card.
 func deal(g *Game) {
-	g.player = make([]deck.Card, 0, 5)
+	g.state = statePlayerTurn
 	g.dealer = make([]deck.Card, 0, 5)
-	var card deck.Card
+	for _, s := range g.seats {
+		s.hands = []hand{{cards: make([]deck.Card, 0, 5), bet: s.bet}}
+		s.handIdx = 0
+		s.insurance = 0
+		s.pending = nil
+	}
 	for i := 0; i < 2; i++ {
-		card, g.deck = draw(g.deck)
-		g.player = append(g.player, card)
-		card, g.deck = draw(g.deck)
-		g.dealer = append(g.dealer, card)
+		for j, s := range g.seats {
+			s.hands[0].cards = append(s.hands[0].cards, draw(g, j, 0))
+		}
+		if i == 1 && g.rules.NoHoleCard {
+			continue
+		}
+		g.dealer = append(g.dealer, draw(g, DealerSeat, 0))
+	}
+	g.seatIdx = 0
+}
+
+// offerInsurance offers insurance, or even money on a blackjack, to every
+// seat whose AI implements Insurer when the dealer shows an Ace. Insurance
+// costs half the bet and is settled at the end of the round.
+func offerInsurance(g *Game) {
+	if g.dealer[0].Rank != deck.Ace {
+		return
+	}
+	for i, s := range g.seats {
+		insurer, ok := s.ai.(Insurer)
+		if !ok {
+			continue
+		}
+		h := &s.hands[0]
+		cards := make([]deck.Card, len(h.cards))
+		copy(cards, h.cards)
+		if !insurer.Insurance(cards, g.dealer[0]) {
+			continue
+		}
+		if natural(h.cards...) {
+			h.evenMoney = true
+			emit(g, Event{Type: EventInsurance, Seat: i, Move: "even money"})
+			continue
+		}
+		s.insurance = float64(h.bet) / 2
+		emit(g, Event{Type: EventInsurance, Seat: i, Amount: s.insurance})
+	}
+}
+
+// errDeferred is returned by every move but MoveSurrender while a player
+// decides whether to surrender early.
+var errDeferred = errors.New("blackjack: move deferred until after the peek")
+
+// earlySurrender asks every seat for its first move before the dealer checks
+// for a blackjack. Surrendering takes effect straight away; any other move is
+// kept and made once the dealer has peeked.
+func earlySurrender(g *Game) {
+	if g.rules.Surrender != SurrenderEarly {
+		return
+	}
+	g.early = true
+	defer func() { g.early = false }()
+	for i, s := range g.seats {
+		if over(s) {
+			continue
+		}
+		g.seatIdx, g.state = i, statePlayerTurn
+		hand := make([]deck.Card, len(s.hands[0].cards))
+		copy(hand, s.hands[0].cards)
+		move := s.ai.Play(hand, g.dealer[0])
+		var err error
+		if move == nil {
+			err = fmt.Errorf("%w: no move was made", ErrIllegalMove)
+		} else {
+			err = move(g)
+		}
+		switch {
+		case err == errDeferred:
+			s.pending = move
+		case err != nil:
+			emit(g, Event{Type: EventMoveRefused, Seat: i, Error: err.Error()})
+			if h, ok := s.ai.(MoveErrorHandler); ok {
+				h.MoveError(err)
+			}
+		}
+	}
+}
+
+// peek has the dealer check the hole card for a blackjack, in which case the
+// round is over straight away. Otherwise the first seat with a hand to play
+// is up, or the dealer if no seat has anything to play.
+func peek(g *Game) {
+	if natural(g.dealer...) {
+		emit(g, Event{Type: EventDealerRevealed, Seat: DealerSeat, Cards: g.dealer})
+		g.state = stateHandOver
+		return
 	}
 	g.state = statePlayerTurn
+	g.seatIdx = -1
+	nextSeat(g)
 }
 
-func (g *Game) Play(ai AI) int {
-	g.deck = deck.New(deck.Deck(3), deck.Shuffle())
-	for i := 0; i < 2; i++ {
-		bet(g, ai)
-		deal(g)
-		for g.state == statePlayerTurn {
-			hand := make([]deck.Card, len(g.player))
-			copy(hand, g.player)
-			move := ai.Play(hand, g.dealer[0])
-			move(g)
+// over reports whether a seat has nothing to play after the deal: it has a
+// blackjack, took even money or surrendered early.
+func over(s *seat) bool {
+	h := s.hands[0]
+	return len(s.hands) == 1 && (natural(h.cards...) || h.surrendered)
+}
+
+// Play plays Options.Hands rounds with ai as the only player and returns its
+// balance and the outcome of every round. See PlayTable.
+func (g *Game) Play(ai AI) (float64, []Round, error) {
+	balances, rounds, err := g.PlayTable(ai)
+	if len(balances) == 0 {
+		return 0, rounds, err
+	}
+	return balances[0], rounds, err
+}
+
+// PlayTable seats up to MaxSeats AIs at the table, in the order given, and
+// plays Options.Hands rounds against the dealer. Every round each seat bets
+// and plays its hands in turn from the game's shoe, which is reshuffled
+// between rounds once the cut card has been reached. It returns the balance
+// of every seat and the outcome of every round, one Round per seat. If the
+// game gets into an invalid state it stops and returns what was played until
+// then along with the error.
+func (g *Game) PlayTable(ais ...AI) ([]float64, []Round, error) {
+	if len(ais) == 0 || len(ais) > MaxSeats {
+		return nil, nil, fmt.Errorf("%w: got %d", ErrSeats, len(ais))
+	}
+	g.sit(ais...)
+	start := len(g.rounds)
+	var err error
+	for i := 0; i < g.nHands && err == nil; i++ {
+		if err = playRound(g); err == nil {
+			writeRound(g, g.rounds[len(g.rounds)-len(g.seats):])
 		}
+	}
+	balances := make([]float64, len(g.seats))
+	for i, s := range g.seats {
+		balances[i] = s.balance
+	}
+	rounds := make([]Round, len(g.rounds)-start)
+	copy(rounds, g.rounds[start:])
+	return balances, rounds, err
+}
 
-		for g.state == stateDealerTurn {
-			hand := make([]deck.Card, len(g.dealer))
-			copy(hand, g.dealer)
-			move := g.dealerAI.Play(hand, g.dealer[0])
-			move(g)
+// writeRound writes the outcome of a round to Options.Output, if set.
+func writeRound(g *Game, rounds []Round) {
+	if g.opts.Output == nil {
+		return
+	}
+	for _, r := range rounds {
+		hands := make([]string, len(r.Hands))
+		for i, h := range r.Hands {
+			hands[i] = deck.Symbols(false, h.Cards...)
 		}
+		fmt.Fprintf(g.opts.Output, "round %d, seat %d: %s against %s: %+g\n",
+			g.round, r.Seat, strings.Join(hands, " | "), deck.Symbols(false, r.Dealer...), r.Winnings)
+	}
+}
+
+// playRound plays a single round at the table.
+func playRound(g *Game) error {
+	if len(g.seats) == 0 {
+		return fmt.Errorf("%w: got 0", ErrSeats)
+	}
+	if g.round == 0 {
+		emit(g, Event{Type: EventShuffled, Seat: DealerSeat, Commitment: g.shoe.Seed().Commitment()})
+	}
+	if g.shoe.NeedsReshuffle() {
+		reshuffle(g)
+	}
+	g.round++
+	emit(g, Event{Type: EventRoundStarted, Seat: DealerSeat})
+	if err := bet(g); err != nil {
+		return err
+	}
+	deal(g)
+	offerInsurance(g)
+	earlySurrender(g)
+	peek(g)
+	if err := playerTurn(g); err != nil {
+		return err
+	}
+	if err := dealerTurn(g); err != nil {
+		return err
+	}
+	endRound(g)
+	return nil
+}
 
-		endRound(g, ai)
+// dealerTurn reveals the dealer's hole card, or deals it without a hole card,
+// and plays the dealer's hand unless every hand at the table is already
+// settled by a bust, a blackjack or a surrender.
+func dealerTurn(g *Game) error {
+	if g.state != stateDealerTurn {
+		return nil
 	}
-	return g.balance
+	if len(g.dealer) == 1 {
+		g.dealer = append(g.dealer, draw(g, DealerSeat, 0))
+	}
+	emit(g, Event{Type: EventDealerRevealed, Seat: DealerSeat, Cards: g.dealer})
+	settled := true
+	for _, s := range g.seats {
+		for _, h := range s.hands {
+			settled = settled && (Score(h.cards...) > 21 || isNatural(s, h) || h.surrendered)
+		}
+	}
+	if settled || natural(g.dealer...) {
+		g.state = stateHandOver
+		return nil
+	}
+	for g.state == stateDealerTurn {
+		hand := make([]deck.Card, len(g.dealer))
+		copy(hand, g.dealer)
+		move := g.dealerAI.Play(hand, g.dealer[0])
+		if err := move(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rounds returns the outcome of every round played so far, one Round per
+// seat in seat order.
+func (g *Game) Rounds() []Round {
+	ret := make([]Round, len(g.rounds))
+	copy(ret, g.rounds)
+	return ret
 }
 
-type Move func(*Game)
+// ErrIllegalMove is returned, usually wrapped with the reason, by a Move that
+// cannot be made in the current state of the game.
+var ErrIllegalMove = errors.New("blackjack: illegal move")
 
-func MoveHit(g *Game) {
-	hand := g.currentHand()
-	var card deck.Card
-	card, g.deck = draw(g.deck)
-	*hand = append(*hand, card)
+// maxIllegalMoves is the number of illegal moves in a row after which the
+// player's hand is stood.
+const maxIllegalMoves = 3
+
+// playerTurn asks each seat's AI in turn to play its hands. Illegal moves are
+// reported to the AI if it implements MoveErrorHandler, and the AI is asked
+// to play the same hand again. Any other error stops the turn.
+func playerTurn(g *Game) error {
+	refused := 0
+	for g.state == statePlayerTurn {
+		current, err := g.currentHand()
+		if err != nil {
+			return err
+		}
+		s := g.seat()
+		move := s.pending
+		s.pending = nil
+		if move == nil {
+			hand := make([]deck.Card, len(*current))
+			copy(hand, *current)
+			move = s.ai.Play(hand, g.dealer[0])
+		}
+		if move == nil {
+			err = fmt.Errorf("%w: no move was made", ErrIllegalMove)
+		} else {
+			err = move(g)
+		}
+		switch {
+		case err == nil:
+			refused = 0
+			continue
+		case !errors.Is(err, ErrIllegalMove):
+			return err
+		}
+		emit(g, Event{Type: EventMoveRefused, Seat: g.seatIdx, Hand: s.handIdx, Error: err.Error()})
+		if h, ok := s.ai.(MoveErrorHandler); ok {
+			h.MoveError(err)
+		}
+		refused++
+		if refused >= maxIllegalMoves {
+			refused = 0
+			MoveStand(g)
+		}
+	}
+	return nil
+}
+
+type Move func(*Game) error
+
+func MoveHit(g *Game) error {
+	if g.early {
+		return errDeferred
+	}
+	if g.state == statePlayerTurn {
+		s := g.seat()
+		if s.hands[s.handIdx].splitAces && g.rules.SplitAcesOneCard {
+			return fmt.Errorf("%w: split aces receive one card only", ErrIllegalMove)
+		}
+	}
+	hand, err := g.currentHand()
+	if err != nil {
+		return err
+	}
+	moved(g, "hit")
+	seat, idx := g.turn()
+	*hand = append(*hand, draw(g, seat, idx))
 	if Score(*hand...) > 21 {
-		MoveStand(g)
+		stand(g)
 	}
+	return nil
 }
 
-func MoveStand(g *Game) {
+func MoveStand(g *Game) error {
+	if g.early {
+		return errDeferred
+	}
+	if g.state == stateHandOver {
+		return ErrInvalidState
+	}
+	moved(g, "stand")
+	stand(g)
+	return nil
+}
+
+// stand ends the current hand.
+func stand(g *Game) {
+	if g.state == statePlayerTurn {
+		nextHand(g)
+		return
+	}
 	g.state++
 }
 
-func draw(cards []deck.Card) (deck.Card, []deck.Card) {
-	return cards[0], cards[1:]
+// turn returns the seat, or DealerSeat, and the hand being played.
+func (g *Game) turn() (int, int) {
+	if g.state != statePlayerTurn {
+		return DealerSeat, 0
+	}
+	return g.seatIdx, g.seat().handIdx
+}
+
+// moved tells the observers about a move being made.
+func moved(g *Game, name string) {
+	seat, hand := g.turn()
+	emit(g, Event{Type: EventMoveTaken, Seat: seat, Hand: hand, Move: name, Early: g.early})
 }
 
-func endRound(g *Game, ai AI) {
-	pScore, dScore := Score(g.player...), Score(g.dealer...)
-	winnings := g.playerBet
+// MoveDouble doubles the bet on the current hand, deals it exactly one more
+// card and ends it. Only the first two cards of a hand can be doubled on.
+func MoveDouble(g *Game) error {
+	if g.early {
+		return errDeferred
+	}
+	if g.state == stateHandOver {
+		return ErrInvalidState
+	}
+	if g.state != statePlayerTurn {
+		return fmt.Errorf("%w: only the player can double", ErrIllegalMove)
+	}
+	s := g.seat()
+	h := &s.hands[s.handIdx]
+	if len(h.cards) != 2 {
+		return fmt.Errorf("%w: can only double on the first two cards", ErrIllegalMove)
+	}
+	if len(s.hands) > 1 && (!g.rules.DoubleAfterSplit || (h.splitAces && g.rules.SplitAcesOneCard)) {
+		return fmt.Errorf("%w: cannot double after splitting", ErrIllegalMove)
+	}
+	score := Score(h.cards...)
 	switch {
-	case pScore > 21:
-		winnings = -1 * winnings
-	case dScore > 21:
-		// win
-	case pScore > dScore:
-		// win
-	case dScore > pScore:
-		winnings = -1 * winnings
-	case dScore == pScore:
-		winnings = 0
-	}
-	g.balance += winnings
-	fmt.Println()
-	ai.Results([][]deck.Card{g.player}, g.dealer)
-	g.player = nil
+	case g.rules.DoubleOn == DoubleNineToEleven && (score < 9 || score > 11):
+		return fmt.Errorf("%w: can only double on 9, 10 or 11", ErrIllegalMove)
+	case g.rules.DoubleOn == DoubleTenToEleven && (score < 10 || score > 11):
+		return fmt.Errorf("%w: can only double on 10 or 11", ErrIllegalMove)
+	}
+	moved(g, "double")
+	h.bet *= 2
+	h.cards = append(h.cards, draw(g, g.seatIdx, s.handIdx))
+	stand(g)
+	return nil
+}
+
+// MoveSurrender gives up the hand in exchange for half the bet. It is only
+// allowed on the first two cards, before any split, and only if the rules
+// allow surrendering. A late surrender still loses the whole bet if the
+// dealer turns out to have a blackjack, which can only happen without a hole
+// card.
+func MoveSurrender(g *Game) error {
+	if g.state == stateHandOver {
+		return ErrInvalidState
+	}
+	if g.rules.Surrender == SurrenderNone {
+		return fmt.Errorf("%w: surrender is not allowed", ErrIllegalMove)
+	}
+	if g.state != statePlayerTurn {
+		return fmt.Errorf("%w: only the player can surrender", ErrIllegalMove)
+	}
+	s := g.seat()
+	h := &s.hands[s.handIdx]
+	if len(s.hands) != 1 || len(h.cards) != 2 {
+		return fmt.Errorf("%w: can only surrender the first two cards", ErrIllegalMove)
+	}
+	moved(g, "surrender")
+	h.surrendered = true
+	nextHand(g)
+	return nil
+}
+
+// MoveSplit splits a pair into two hands, each with a bet equal to the
+// original one. The hands are then played one after the other.
+func MoveSplit(g *Game) error {
+	if g.early {
+		return errDeferred
+	}
+	if g.state == stateHandOver {
+		return ErrInvalidState
+	}
+	if g.state != statePlayerTurn {
+		return fmt.Errorf("%w: only the player can split", ErrIllegalMove)
+	}
+	s := g.seat()
+	h := &s.hands[s.handIdx]
+	if len(h.cards) != 2 || h.cards[0].Rank != h.cards[1].Rank {
+		return fmt.Errorf("%w: only a pair can be split", ErrIllegalMove)
+	}
+	if len(s.hands) >= g.rules.MaxSplitHands {
+		return fmt.Errorf("%w: no more than %d hands can be played", ErrIllegalMove, g.rules.MaxSplitHands)
+	}
+	aces := h.cards[0].Rank == deck.Ace
+	if aces && h.splitAces && !g.rules.ResplitAces {
+		return fmt.Errorf("%w: split aces cannot be split again", ErrIllegalMove)
+	}
+	moved(g, "split")
+	split := hand{cards: []deck.Card{h.cards[1]}, bet: h.bet, splitAces: aces}
+	h.cards = h.cards[:1]
+	h.splitAces = aces
+	h.cards = append(h.cards, draw(g, g.seatIdx, s.handIdx))
+
+	s.hands = append(s.hands, hand{})
+	copy(s.hands[s.handIdx+2:], s.hands[s.handIdx+1:])
+	s.hands[s.handIdx+1] = split
+	if done(g, s.hands[s.handIdx]) {
+		nextHand(g)
+	}
+	return nil
+}
+
+// nextHand moves on to the seat's next hand, dealing the second card to
+// hands created by a split. Once every hand of the seat has been played it
+// is the next seat's turn.
+func nextHand(g *Game) {
+	s := g.seat()
+	for s.handIdx++; s.handIdx < len(s.hands); s.handIdx++ {
+		h := &s.hands[s.handIdx]
+		if len(h.cards) == 1 {
+			h.cards = append(h.cards, draw(g, g.seatIdx, s.handIdx))
+		}
+		if !done(g, *h) {
+			return
+		}
+	}
+	nextSeat(g)
+}
+
+// nextSeat moves on to the next seat with a hand to play. Once every seat
+// has played it is the dealer's turn.
+func nextSeat(g *Game) {
+	for g.seatIdx++; g.seatIdx < len(g.seats); g.seatIdx++ {
+		s := g.seat()
+		s.handIdx = 0
+		if !over(s) {
+			return
+		}
+	}
+	g.seatIdx = len(g.seats) - 1
+	g.state = stateDealerTurn
+}
+
+// done reports whether a hand of the current seat that was just dealt its
+// second card is over without the player having to do anything: split aces
+// that receive one card only are over, unless they can be split again.
+func done(g *Game, h hand) bool {
+	if !h.splitAces || !g.rules.SplitAcesOneCard {
+		return false
+	}
+	resplit := g.rules.ResplitAces && h.cards[1].Rank == deck.Ace && len(g.seat().hands) < g.rules.MaxSplitHands
+	return !resplit
+}
+
+// draw deals the next card from the shoe to a hand of seat, or to the
+// dealer. A shoe that runs out in the middle of a round is reshuffled
+// straight away, without the cards on the table, so that no card is dealt
+// twice in a round.
+func draw(g *Game, seat, hand int) deck.Card {
+	card, err := g.shoe.Draw()
+	if err != nil {
+		reshuffle(g, g.inPlay()...)
+		if card, err = g.shoe.Draw(); err != nil {
+			// every card of the shoe is on the table
+			reshuffle(g)
+			card, _ = g.shoe.Draw()
+		}
+	}
+	if len(g.observers) > 0 {
+		e := Event{Type: EventCardDealt, Seat: seat, Hand: hand}
+		if seat == DealerSeat && len(g.dealer) == 1 && g.state == statePlayerTurn {
+			e.Hidden = true
+		} else {
+			dealt := card
+			e.Card = &dealt
+		}
+		emit(g, e)
+	}
+	return card
+}
+
+// inPlay returns the cards on the table: those of the dealer and of every
+// hand of the round.
+func (g *Game) inPlay() []deck.Card {
+	ret := append([]deck.Card(nil), g.dealer...)
+	for _, s := range g.seats {
+		for _, h := range s.hands {
+			ret = append(ret, h.cards...)
+		}
+	}
+	return ret
+}
+
+// reshuffle reshuffles the shoe, less the cards in play, and tells every seat
+// whose AI implements ShuffleWatcher.
+func reshuffle(g *Game, inPlay ...deck.Card) {
+	g.shoe.ReshuffleExcept(inPlay...)
+	emit(g, Event{Type: EventShuffled, Seat: DealerSeat, Commitment: g.shoe.Seed().Commitment()})
+	for _, s := range g.seats {
+		if w, ok := s.ai.(ShuffleWatcher); ok {
+			w.Shuffled()
+		}
+	}
+}
+
+// endRound settles every seat against the dealer and shows each AI its
+// hands. AIs that implement TableWatcher are also shown the other seats'
+// hands.
+func endRound(g *Game) {
+	hands := make([][][]deck.Card, len(g.seats))
+	for i, s := range g.seats {
+		round := settle(g, s)
+		round.Seat = i
+		s.balance += round.Winnings
+		g.rounds = append(g.rounds, round)
+		emit(g, Event{Type: EventRoundSettled, Seat: i, Amount: round.Winnings, Result: &round})
+		hands[i] = make([][]deck.Card, len(s.hands))
+		for j, h := range s.hands {
+			hands[i][j] = h.cards
+		}
+	}
+	for i, s := range g.seats {
+		s.ai.Results(hands[i], g.dealer)
+		if w, ok := s.ai.(TableWatcher); ok {
+			var others [][]deck.Card
+			for j := range hands {
+				if j != i {
+					others = append(others, hands[j]...)
+				}
+			}
+			w.Watch(others, g.dealer)
+		}
+	}
+	for _, s := range g.seats {
+		s.hands = nil
+	}
 	g.dealer = nil
 }
 
+// settle works out what a seat won or lost on each of its hands and on
+// insurance.
+func settle(g *Game, s *seat) Round {
+	dScore, dNatural := Score(g.dealer...), natural(g.dealer...)
+	round := Round{
+		Hands:     make([]HandResult, len(s.hands)),
+		Dealer:    g.dealer,
+		Insurance: s.insurance,
+	}
+	if s.insurance > 0 {
+		round.InsuranceWinnings = -1 * s.insurance
+		if dNatural {
+			round.InsuranceWinnings = 2 * s.insurance
+		}
+	}
+	round.Winnings = round.InsuranceWinnings
+	for i, h := range s.hands {
+		pScore, pNatural := Score(h.cards...), isNatural(s, h)
+		winnings := float64(h.bet)
+		switch {
+		case h.evenMoney:
+			// paid 1:1 whatever the dealer has
+		case h.surrendered && !(dNatural && g.rules.Surrender == SurrenderLate):
+			winnings = -1 * winnings / 2
+		case pNatural && dNatural:
+			winnings = 0
+		case pNatural:
+			winnings = winnings * g.rules.BlackJackPayout
+		case dNatural:
+			winnings = -1 * winnings
+		case pScore > 21:
+			winnings = -1 * winnings
+		case dScore > 21:
+			// win
+		case pScore > dScore:
+			// win
+		case dScore > pScore:
+			winnings = -1 * winnings
+		case dScore == pScore:
+			winnings = 0
+		}
+		round.Hands[i] = HandResult{
+			Cards:       h.cards,
+			Bet:         h.bet,
+			EvenMoney:   h.evenMoney,
+			Surrendered: h.surrendered,
+			Winnings:    winnings,
+		}
+		round.Winnings += winnings
+	}
+	return round
+}
+
 // Score will take in a hand of cards and return the best blackjack score
 // possible with the hand.
 func Score(hand ...deck.Card) int {
@@ -170,6 +830,17 @@ func Score(hand ...deck.Card) int {
 	return minScore
 }
 
+// natural returns true if the hand is a blackjack, i.e. 21 with two cards.
+func natural(hand ...deck.Card) bool {
+	return len(hand) == 2 && Score(hand...) == 21
+}
+
+// isNatural returns true if h is a blackjack. 21 with two cards after a split
+// is not a blackjack.
+func isNatural(s *seat, h hand) bool {
+	return len(s.hands) == 1 && natural(h.cards...)
+}
+
 // Soft returns true if the score of a hand is a soft score - that is if an ace
 // is being counted as 11 points.
 func Soft(hand ...deck.Card) bool {
@@ -0,0 +1,607 @@
+package blackjack
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"deck"
+)
+
+// stackShuffler "shuffles" a shoe by putting its cards first, followed by
+// low cards so that the dealer never runs out.
+type stackShuffler []deck.Card
+
+func (s stackShuffler) Shuffle(cards []deck.Card) deck.Seed {
+	for i := range cards {
+		cards[i] = deck.Card{Rank: deck.Two, Suit: deck.Club}
+	}
+	copy(cards, s)
+	return deck.Seed{}
+}
+
+// stacked returns a shoe that deals cards in the given order.
+func stacked(t *testing.T, s string) *deck.Shoe {
+	cards, err := deck.ParseHand(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deck.NewShoe(deck.ShoeOptions{Shuffler: stackShuffler(cards)})
+}
+
+// newRound deals a round with a single seat betting 1 from cards, which
+// alternate between the player and the dealer for the first four cards.
+func newRound(t *testing.T, opts Options, cards string) *Game {
+	g := New(opts)
+	g.shoe = stacked(t, cards)
+	g.sit(&scriptedAI{})
+	bet(&g)
+	deal(&g)
+	peek(&g)
+	return &g
+}
+
+func TestSplit(t *testing.T) {
+	g := newRound(t, Options{}, "8s Ts 8h 7d 3c 9d")
+	if err := MoveSplit(g); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.seats[0].hands) != 2 || g.seats[0].handIdx != 0 {
+		t.Fatalf("expected 2 hands playing the first, got %d playing %d", len(g.seats[0].hands), g.seats[0].handIdx)
+	}
+	if Score(g.seats[0].hands[0].cards...) != 11 {
+		t.Errorf("expected the first hand to be 8 and 3, got: %s", Hand(g.seats[0].hands[0].cards))
+	}
+	MoveStand(g)
+	if g.seats[0].handIdx != 1 || Score(g.seats[0].hands[1].cards...) != 17 {
+		t.Errorf("expected the second hand to be 8 and 9, got: %s", Hand(g.seats[0].hands[1].cards))
+	}
+	if g.seats[0].hands[1].bet != 1 {
+		t.Errorf("expected the split hand to have its own bet of 1, got: %d", g.seats[0].hands[1].bet)
+	}
+	MoveStand(g)
+	if g.state != stateDealerTurn {
+		t.Errorf("expected the dealer's turn once both hands are played")
+	}
+}
+
+func TestSplitIllegal(t *testing.T) {
+	g := newRound(t, Options{}, "8s Ts 9h 7d")
+	if err := MoveSplit(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected %v, got: %v", ErrIllegalMove, err)
+	}
+	g = newRound(t, Options{Rules: Rules{MaxSplitHands: 1}}, "8s Ts 8h 7d")
+	if err := MoveSplit(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected splitting to be forbidden, got: %v", err)
+	}
+}
+
+func TestResplitLimit(t *testing.T) {
+	g := newRound(t, Options{Rules: Rules{MaxSplitHands: 3}}, "8s Ts 8h 7d 8c 8d 8h")
+	for i := 0; i < 2; i++ {
+		if err := MoveSplit(g); err != nil {
+			t.Fatalf("split %d: %v", i, err)
+		}
+	}
+	if err := MoveSplit(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected no more than 3 hands, got: %v", err)
+	}
+}
+
+func TestSplitAcesOneCard(t *testing.T) {
+	g := newRound(t, Options{Rules: Rules{SplitAcesOneCard: true}}, "As Ts Ah 7d 5c Ad")
+	if err := MoveSplit(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.state != stateDealerTurn {
+		t.Fatalf("expected both split aces to be over after one card each")
+	}
+	if len(g.seats[0].hands[0].cards) != 2 || len(g.seats[0].hands[1].cards) != 2 {
+		t.Errorf("expected each ace to receive one card")
+	}
+
+	g = newRound(t, Options{Rules: Rules{SplitAcesOneCard: true, ResplitAces: true}}, "As Ts Ah 7d Ac 5d 6h 7h")
+	if err := MoveSplit(g); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveHit(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected hitting split aces to be refused, got: %v", err)
+	}
+	if err := MoveSplit(g); err != nil {
+		t.Fatalf("expected aces to be resplit, got: %v", err)
+	}
+	if len(g.seats[0].hands) != 3 || g.state != stateDealerTurn {
+		t.Errorf("expected 3 finished hands, got %d", len(g.seats[0].hands))
+	}
+}
+
+func TestEndRoundSettlesEveryHand(t *testing.T) {
+	g := newRound(t, Options{}, "8s Ts 8h 8d Tc 3d")
+	MoveSplit(g)
+	MoveStand(g)
+	MoveStand(g)
+	for g.state == stateDealerTurn {
+		MoveStand(g)
+	}
+	// 18 against the dealer's 18 pushes, 11 loses
+	endRound(g)
+	if g.seats[0].balance != -1 {
+		t.Errorf("expected a balance of -1, got: %v", g.seats[0].balance)
+	}
+}
+
+func TestDouble(t *testing.T) {
+	g := newRound(t, Options{}, "6s Ts 5h 7d 9c")
+	if err := MoveDouble(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.seats[0].hands[0].bet != 2 || len(g.seats[0].hands[0].cards) != 3 {
+		t.Errorf("expected a bet of 2 on 3 cards, got %d on %d", g.seats[0].hands[0].bet, len(g.seats[0].hands[0].cards))
+	}
+	if g.state != stateDealerTurn {
+		t.Errorf("expected doubling to end the hand")
+	}
+}
+
+func TestDoubleRestrictions(t *testing.T) {
+	tests := []struct {
+		opts  Options
+		cards string
+		legal bool
+	}{
+		{Options{Rules: Rules{DoubleOn: DoubleNineToEleven}}, "5s Ts 4h 7d", true},
+		{Options{Rules: Rules{DoubleOn: DoubleNineToEleven}}, "5s Ts 3h 7d", false},
+		{Options{Rules: Rules{DoubleOn: DoubleTenToEleven}}, "5s Ts 4h 7d", false},
+		{Options{Rules: Rules{DoubleOn: DoubleTenToEleven}}, "5s Ts 6h 7d", true},
+		{Options{Rules: Rules{DoubleOn: DoubleTenToEleven}}, "As Ts 9h 7d", false},
+	}
+	for _, tt := range tests {
+		g := newRound(t, tt.opts, tt.cards)
+		if err := MoveDouble(g); (err == nil) != tt.legal {
+			t.Errorf("%s: expected legal to be %v, got: %v", tt.cards, tt.legal, err)
+		}
+	}
+	g := newRound(t, Options{}, "6s Ts 5h 7d 9c")
+	MoveHit(g)
+	if err := MoveDouble(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected doubling on three cards to be refused, got: %v", err)
+	}
+}
+
+func TestDoubleAfterSplit(t *testing.T) {
+	g := newRound(t, Options{}, "5s Ts 5h 7d 6c 6d")
+	MoveSplit(g)
+	if err := MoveDouble(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected doubling after a split to be refused, got: %v", err)
+	}
+	g = newRound(t, Options{Rules: Rules{DoubleAfterSplit: true}}, "5s Ts 5h 7d 6c 6d")
+	MoveSplit(g)
+	if err := MoveDouble(g); err != nil {
+		t.Errorf("expected doubling after a split to be allowed, got: %v", err)
+	}
+}
+
+type scriptedAI struct {
+	bet    int
+	moves  []Move
+	errors []error
+}
+
+func (ai *scriptedAI) Bet() int {
+	if ai.bet == 0 {
+		return 1
+	}
+	return ai.bet
+}
+
+func (ai *scriptedAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	if len(ai.moves) == 0 {
+		return MoveStand
+	}
+	move := ai.moves[0]
+	ai.moves = ai.moves[1:]
+	return move
+}
+
+func (ai *scriptedAI) MoveError(err error) {
+	ai.errors = append(ai.errors, err)
+}
+
+func (ai *scriptedAI) Results(hand [][]deck.Card, dealer []deck.Card) {}
+
+func TestIllegalMovesAreReported(t *testing.T) {
+	g := newRound(t, Options{}, "6s Ts 5h 7d 9c")
+	ai := &scriptedAI{moves: []Move{MoveSplit, MoveDouble}}
+	g.seats[0].ai = ai
+	playerTurn(g)
+	if len(ai.errors) != 1 || !errors.Is(ai.errors[0], ErrIllegalMove) {
+		t.Errorf("expected the split to be reported as illegal, got: %v", ai.errors)
+	}
+	if g.seats[0].hands[0].bet != 2 {
+		t.Errorf("expected the double to go through after the illegal split")
+	}
+
+	g = newRound(t, Options{}, "6s Ts 5h 7d 9c")
+	ai = &scriptedAI{moves: []Move{MoveSplit, MoveSplit, MoveSplit, MoveSplit}}
+	g.seats[0].ai = ai
+	playerTurn(g)
+	if len(ai.errors) != maxIllegalMoves || g.state != stateDealerTurn {
+		t.Errorf("expected the hand to be stood after %d illegal moves", maxIllegalMoves)
+	}
+}
+
+// finish plays the dealer's hand and settles the round.
+func finish(g *Game) {
+	dealerTurn(g)
+	endRound(g)
+}
+
+func TestNaturals(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		cards string
+		state state
+		want  float64
+	}{
+		{"3:2", Options{}, "As 9s Kh 7d", stateDealerTurn, 1.5},
+		{"6:5", Options{Rules: Rules{BlackJackPayout: 1.2}}, "As 9s Kh 7d", stateDealerTurn, 1.2},
+		{"dealer peeks", Options{}, "9s As Kh Kd", stateHandOver, -1},
+		{"both naturals push", Options{}, "As Ad Kh Kd", stateHandOver, 0},
+		{"no hole card", Options{Rules: Rules{NoHoleCard: true}}, "As Ad Kh 9d", stateDealerTurn, 1.5},
+	}
+	for _, tt := range tests {
+		g := newRound(t, tt.opts, tt.cards)
+		if g.state != tt.state {
+			t.Errorf("%s: expected state %d after the deal, got: %d", tt.name, tt.state, g.state)
+		}
+		finish(g)
+		if g.seats[0].balance != tt.want {
+			t.Errorf("%s: expected a balance of %v, got: %v", tt.name, tt.want, g.seats[0].balance)
+		}
+	}
+}
+
+func TestNoHoleCardDealerBlackjack(t *testing.T) {
+	g := newRound(t, Options{Rules: Rules{NoHoleCard: true}}, "5s As 6h 9c Kd")
+	if len(g.dealer) != 1 || g.state != statePlayerTurn {
+		t.Fatalf("expected the dealer to have a single card and the player to play")
+	}
+	if err := MoveDouble(g); err != nil {
+		t.Fatal(err)
+	}
+	finish(g)
+	if g.seats[0].balance != -2 {
+		t.Errorf("expected the dealer blackjack to take the doubled bet, got: %v", g.seats[0].balance)
+	}
+}
+
+func TestSplitTwentyOneIsNotNatural(t *testing.T) {
+	g := newRound(t, Options{}, "As Ts Ah 8d Kc 9d")
+	MoveSplit(g)
+	MoveStand(g)
+	MoveStand(g)
+	finish(g)
+	// 21 wins even money and 20 wins even money against 18
+	if g.seats[0].balance != 2 {
+		t.Errorf("expected a balance of 2, got: %v", g.seats[0].balance)
+	}
+}
+
+type insuranceAI struct {
+	scriptedAI
+	insure bool
+}
+
+func (ai *insuranceAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	return ai.insure
+}
+
+func TestInsurance(t *testing.T) {
+	tests := []struct {
+		name      string
+		bet       int
+		cards     string
+		insurance float64
+		want      float64
+	}{
+		{"dealer blackjack", 2, "9s As 9h Kd", 2, 0},
+		{"no dealer blackjack", 2, "Ts As Th 7d", -1, 1},
+		{"even money", 2, "As Ad Kh Kd", 0, 2},
+		{"even money without dealer blackjack", 2, "As Ad Kh 6d", 0, 2},
+	}
+	for _, tt := range tests {
+		g := New(Options{})
+		g.shoe = stacked(t, tt.cards)
+		g.sit(&insuranceAI{scriptedAI: scriptedAI{bet: tt.bet}, insure: true})
+		bet(&g)
+		deal(&g)
+		offerInsurance(&g)
+		peek(&g)
+		playerTurn(&g)
+		finish(&g)
+		round := g.Rounds()[0]
+		if round.InsuranceWinnings != tt.insurance || round.Winnings != tt.want {
+			t.Errorf("%s: expected insurance %v and winnings %v, got: %v and %v",
+				tt.name, tt.insurance, tt.want, round.InsuranceWinnings, round.Winnings)
+		}
+	}
+}
+
+func TestNoInsuranceWithoutAce(t *testing.T) {
+	g := New(Options{})
+	g.shoe = stacked(t, "9s Ks 9h Ad")
+	g.sit(&insuranceAI{scriptedAI: scriptedAI{bet: 2}, insure: true})
+	bet(&g)
+	deal(&g)
+	offerInsurance(&g)
+	if g.seats[0].insurance != 0 {
+		t.Errorf("expected no insurance when the dealer doesn't show an ace")
+	}
+}
+
+func TestSurrender(t *testing.T) {
+	g := newRound(t, Options{}, "Ts Ts 6h 7d")
+	if err := MoveSurrender(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected surrender to be refused without a surrender rule, got: %v", err)
+	}
+	g = newRound(t, Options{Rules: Rules{Surrender: SurrenderLate}}, "Ts Ts 6h 7d")
+	if err := MoveSurrender(g); err != nil {
+		t.Fatal(err)
+	}
+	finish(g)
+	if g.seats[0].balance != -0.5 {
+		t.Errorf("expected to lose half the bet, got: %v", g.seats[0].balance)
+	}
+	g = newRound(t, Options{Rules: Rules{Surrender: SurrenderLate}}, "8s Ts 8h 7d 9c 9d")
+	MoveSplit(g)
+	if err := MoveSurrender(g); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected surrender after a split to be refused, got: %v", err)
+	}
+}
+
+func TestLateSurrenderAgainstBlackjack(t *testing.T) {
+	g := newRound(t, Options{Rules: Rules{Surrender: SurrenderLate}}, "Ts As 6h Kd")
+	if g.state != stateHandOver {
+		t.Fatalf("expected the dealer to peek the blackjack")
+	}
+	g = newRound(t, Options{Rules: Rules{Surrender: SurrenderLate, NoHoleCard: true}}, "Ts As 6h Kd")
+	MoveSurrender(g)
+	finish(g)
+	if g.seats[0].balance != -1 {
+		t.Errorf("expected a late surrender to lose it all to a blackjack, got: %v", g.seats[0].balance)
+	}
+}
+
+func TestEarlySurrender(t *testing.T) {
+	g := New(Options{Rules: Rules{Surrender: SurrenderEarly}})
+	g.shoe = stacked(t, "Ts As 6h Kd")
+	g.sit(&scriptedAI{bet: 2, moves: []Move{MoveSurrender}})
+	bet(&g)
+	deal(&g)
+	earlySurrender(&g)
+	peek(&g)
+	finish(&g)
+	if g.seats[0].balance != -1 {
+		t.Errorf("expected an early surrender to lose half the bet to a blackjack, got: %v", g.seats[0].balance)
+	}
+
+	g = New(Options{Rules: Rules{Surrender: SurrenderEarly}})
+	g.shoe = stacked(t, "Ts As 6h 6d 5c")
+	g.sit(&scriptedAI{moves: []Move{MoveHit}})
+	bet(&g)
+	deal(&g)
+	earlySurrender(&g)
+	if len(g.seats[0].hands[0].cards) != 2 || g.seats[0].pending == nil {
+		t.Fatalf("expected the hit to wait until after the peek")
+	}
+	peek(&g)
+	playerTurn(&g)
+	if Score(g.seats[0].hands[0].cards...) != 21 {
+		t.Errorf("expected the pending hit to be made after the peek, got: %s", Hand(g.seats[0].hands[0].cards))
+	}
+
+	g = New(Options{Rules: Rules{Surrender: SurrenderEarly}})
+	g.shoe = stacked(t, "Ts As 6h 6d")
+	ai := &scriptedAI{moves: []Move{nil}}
+	g.sit(ai)
+	if err := playRound(&g); err != nil {
+		t.Fatal(err)
+	}
+	if len(ai.errors) != 1 || !errors.Is(ai.errors[0], ErrIllegalMove) {
+		t.Errorf("expected a missing move to be reported as illegal, got: %v", ai.errors)
+	}
+}
+
+type countingAI struct {
+	scriptedAI
+	rounds int
+}
+
+func (ai *countingAI) Results(hand [][]deck.Card, dealer []deck.Card) {
+	ai.rounds++
+}
+
+func TestPlayHonoursOptions(t *testing.T) {
+	g := New(Options{Rules: Rules{Decks: 2}, Hands: 50, Penetration: 0.5})
+	if g.shoe.Len() != 104 {
+		t.Errorf("expected a shoe of %d cards, got: %d", 104, g.shoe.Len())
+	}
+	reshuffles := 0
+	g.shoe.OnReshuffle(func() { reshuffles++ })
+	ai := &countingAI{}
+	if _, _, err := g.Play(ai); err != nil {
+		t.Fatal(err)
+	}
+	if ai.rounds != 50 {
+		t.Errorf("expected %d rounds, got: %d", 50, ai.rounds)
+	}
+	// 50 rounds use at least 200 cards, so half of a 104 card shoe must have
+	// been reached more than once
+	if reshuffles < 2 {
+		t.Errorf("expected the shoe to be reshuffled, got %d reshuffles", reshuffles)
+	}
+}
+
+func TestFullPenetration(t *testing.T) {
+	g := New(Options{Penetration: 1})
+	if p := g.shoe.Penetration(); p != 0.75 {
+		t.Errorf("expected a penetration of 1 to be replaced by %v, got: %v", 0.75, p)
+	}
+}
+
+func TestNoCardDealtTwiceInARound(t *testing.T) {
+	g := New(Options{Rules: Rules{Decks: 1}, Hands: 2000, Penetration: 0.9, Shuffler: deck.SeededShuffler(3)})
+	ais := make([]AI, MaxSeats)
+	for i := range ais {
+		ais[i] = &BasicStrategyAI{}
+	}
+	_, rounds, err := g.PlayTable(ais...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(rounds); i += MaxSeats {
+		seen := make(map[deck.Card]bool)
+		cards := append([]deck.Card(nil), rounds[i].Dealer...)
+		for _, r := range rounds[i : i+MaxSeats] {
+			for _, h := range r.Hands {
+				cards = append(cards, h.Cards...)
+			}
+		}
+		for _, c := range cards {
+			key := c.Face()
+			key.Deck = c.Deck
+			if seen[key] {
+				t.Fatalf("round %d: %s was dealt twice", i/MaxSeats+1, c.ID())
+			}
+			seen[key] = true
+		}
+	}
+}
+
+func TestDealerSoft17(t *testing.T) {
+	g := newRound(t, Options{}, "Ts As 9h 6d Kc")
+	MoveStand(g)
+	finish(g)
+	if dealer := g.Rounds()[0].Dealer; len(dealer) != 3 {
+		t.Errorf("expected the dealer to hit soft 17 by default, got: %s", Hand(dealer))
+	}
+	g = newRound(t, Options{Rules: Rules{DealerStandsSoft17: true}}, "Ts As 9h 6d Kc")
+	MoveStand(g)
+	finish(g)
+	if dealer := g.Rounds()[0].Dealer; len(dealer) != 2 {
+		t.Errorf("expected the dealer to stand on soft 17, got: %s", Hand(dealer))
+	}
+}
+
+type watchingAI struct {
+	scriptedAI
+	seen int
+}
+
+func (ai *watchingAI) Watch(hands [][]deck.Card, dealer []deck.Card) {
+	ai.seen += len(hands)
+}
+
+func TestTable(t *testing.T) {
+	g := New(Options{})
+	g.shoe = stacked(t, "Ts 9s 5h Td Kh 7c 6h Kd")
+	watcher := &watchingAI{}
+	g.sit(&scriptedAI{}, &scriptedAI{bet: 2}, watcher)
+	bet(&g)
+	deal(&g)
+	peek(&g)
+	if len(g.seats[2].hands[0].cards) != 2 || Score(g.dealer...) != 20 {
+		t.Fatalf("expected every seat to be dealt before the dealer")
+	}
+	playerTurn(&g)
+	finish(&g)
+	// 20 pushes against the dealer's 20, 16 and 11 lose
+	want := []float64{0, -2, -1}
+	for i, s := range g.seats {
+		if s.balance != want[i] {
+			t.Errorf("seat %d: expected a balance of %v, got: %v", i, want[i], s.balance)
+		}
+	}
+	if watcher.seen != 2 {
+		t.Errorf("expected the watcher to see the 2 other hands, got: %d", watcher.seen)
+	}
+	if rounds := g.Rounds(); len(rounds) != 3 || rounds[1].Seat != 1 {
+		t.Errorf("expected a round for every seat, got: %v", rounds)
+	}
+}
+
+func TestPlayTableSeats(t *testing.T) {
+	g := New(Options{})
+	if _, _, err := g.PlayTable(); !errors.Is(err, ErrSeats) {
+		t.Errorf("expected %v, got: %v", ErrSeats, err)
+	}
+	ais := make([]AI, MaxSeats+1)
+	for i := range ais {
+		ais[i] = &countingAI{}
+	}
+	if _, _, err := g.PlayTable(ais...); !errors.Is(err, ErrSeats) {
+		t.Errorf("expected %v, got: %v", ErrSeats, err)
+	}
+	balances, rounds, err := g.PlayTable(ais[:MaxSeats]...)
+	if err != nil || len(balances) != MaxSeats {
+		t.Fatalf("expected %d balances, got: %v (%v)", MaxSeats, balances, err)
+	}
+	if len(rounds) != 2*MaxSeats {
+		t.Errorf("expected %d rounds, got: %d", 2*MaxSeats, len(rounds))
+	}
+	for i, ai := range ais[:MaxSeats] {
+		if ai.(*countingAI).rounds != 2 {
+			t.Errorf("seat %d: expected %d rounds, got: %d", i, 2, ai.(*countingAI).rounds)
+		}
+	}
+}
+
+func TestMovesOutOfTurn(t *testing.T) {
+	fresh := New(Options{})
+	g := newRound(t, Options{}, "Ts 9s 5h 7d")
+	MoveStand(g)
+	finish(g)
+	for _, g := range []*Game{&fresh, g} {
+		for name, move := range moves {
+			if err := move(g); !errors.Is(err, ErrInvalidState) {
+				t.Errorf("%s: expected %v, got: %v", name, ErrInvalidState, err)
+			}
+		}
+	}
+}
+
+type betAI struct {
+	scriptedAI
+	amount int
+}
+
+func (ai *betAI) Bet() int {
+	return ai.amount
+}
+
+func TestInvalidBet(t *testing.T) {
+	for _, b := range []int{0, -5} {
+		g := New(Options{Hands: 200})
+		balance, rounds, err := g.Play(&betAI{amount: b})
+		if !errors.Is(err, ErrInvalidBet) || len(rounds) != 0 || balance != 0 {
+			t.Errorf("bet %d: expected %v before any round is played, got: %v, %d rounds and %v", b, ErrInvalidBet, err, len(rounds), balance)
+		}
+	}
+}
+
+func TestPlayIsSilent(t *testing.T) {
+	g := New(Options{Hands: 3})
+	_, rounds, err := g.Play(&scriptedAI{})
+	if err != nil || len(rounds) != 3 {
+		t.Fatalf("expected 3 rounds, got: %d (%v)", len(rounds), err)
+	}
+
+	var buf bytes.Buffer
+	g = New(Options{Hands: 3, Output: &buf})
+	g.PlayTable(&scriptedAI{}, &scriptedAI{})
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 6 || !strings.HasPrefix(lines[5], "round 3, seat 1:") {
+		t.Errorf("expected a line per seat and round, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,364 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"deck"
+)
+
+// Session is a captured game: its rules and shoe, and every event from its
+// first round on, which holds the decisions of every seat. Seeds holds the
+// seed of every shuffle, as deck.Seed.String does, and Shoes the order of the
+// shoe after it, so that games shuffled any way can be replayed; in JSON its
+// cards are written with their identity, as deck.Card.ID does. Sessions can
+// be stored as JSON and replayed with Replay.
+type Session struct {
+	Rules       Rules         `json:"rules"`
+	Penetration float64       `json:"penetration"`
+	Deck        deck.Spec     `json:"deck"`
+	Events      []Event       `json:"events"`
+	Seeds       []string      `json:"seeds"`
+	Shoes       [][]deck.Card `json:"-"`
+}
+
+// sessionJSON is a Session as written to JSON.
+type sessionJSON struct {
+	session
+	Shoes [][]cardID `json:"shoes"`
+}
+
+type session Session
+
+func (s Session) MarshalJSON() ([]byte, error) {
+	j := sessionJSON{session: session(s)}
+	for _, shoe := range s.Shoes {
+		ids := make([]cardID, len(shoe))
+		for i, c := range shoe {
+			ids[i] = cardID(c)
+		}
+		j.Shoes = append(j.Shoes, ids)
+	}
+	return json.Marshal(j)
+}
+
+func (s *Session) UnmarshalJSON(data []byte) error {
+	var j sessionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*s = Session(j.session)
+	for _, ids := range j.Shoes {
+		shoe := make([]deck.Card, len(ids))
+		for i, c := range ids {
+			shoe[i] = deck.Card(c)
+		}
+		s.Shoes = append(s.Shoes, shoe)
+	}
+	return nil
+}
+
+// Capture starts capturing the game into a Session, which is filled in as
+// the game is played. Only sessions captured before the first round can be
+// replayed. The seed and order of the shoe are read from the game itself
+// rather than sent to observers, which would let AIs see the cards to come.
+func (g *Game) Capture() *Session {
+	s := &Session{
+		Rules:       g.opts.Rules,
+		Penetration: g.opts.Penetration,
+		Deck:        g.opts.Deck,
+	}
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Type == EventShuffled {
+			s.Seeds = append(s.Seeds, g.shoe.Seed().String())
+			s.Shoes = append(s.Shoes, g.shoe.Cards())
+		}
+		s.Events = append(s.Events, e)
+	}))
+	return s
+}
+
+// Rounds returns the outcome of every round of the session, as Game.Rounds
+// does.
+func (s *Session) Rounds() []Round {
+	var ret []Round
+	for _, e := range s.Events {
+		if e.Type == EventRoundSettled && e.Result != nil {
+			ret = append(ret, *e.Result)
+		}
+	}
+	return ret
+}
+
+// size returns the number of seats and rounds of the session.
+func (s *Session) size() (seats, rounds int) {
+	for _, e := range s.Events {
+		if e.Type == EventBetPlaced && e.Seat >= seats {
+			seats = e.Seat + 1
+		}
+		if e.Round > rounds {
+			rounds = e.Round
+		}
+	}
+	return seats, rounds
+}
+
+// ErrReplayMismatch is returned, wrapped with the first difference, when a
+// replay doesn't play out as the session was recorded.
+var ErrReplayMismatch = errors.New("blackjack: replay does not match the session")
+
+// WhatIf substitutes AI for a seat's recorded decisions in a round, from its
+// Decision'th move in the round on, counting from 0.
+type WhatIf struct {
+	Round    int
+	Seat     int
+	Decision int
+	AI       AI
+}
+
+// ReplayOptions configures Replay. Observers are told about every event of
+// the replay as it happens, so that the game can be followed step by step.
+// With WhatIf set the replay stops at the end of WhatIf.Round.
+type ReplayOptions struct {
+	Observers []Observer
+	WhatIf    *WhatIf
+}
+
+// Replay plays a session again from its shoes and recorded decisions, and
+// returns the outcome of every round replayed. Sessions without shoes are
+// replayed from their seeds, which only reproduces the default shuffle.
+// Every event of the replay is checked against the session and
+// ErrReplayMismatch is returned at the first difference; from the round of a
+// WhatIf on the outcome is expected to differ and is no longer checked.
+// Illegal moves are not replayed.
+func Replay(s *Session, opts ReplayOptions) ([]Round, error) {
+	var seeds []deck.Seed
+	for _, str := range s.Seeds {
+		seed, err := deck.ParseSeed(str)
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, seed)
+	}
+	nSeats, nRounds := s.size()
+	if len(seeds) == 0 || nSeats == 0 || s.Events[0].Type != EventShuffled {
+		return nil, errors.New("blackjack: the session wasn't captured from its first round")
+	}
+	if len(s.Shoes) > 0 && len(s.Shoes) != len(seeds) {
+		return nil, fmt.Errorf("blackjack: the session has %d shoes for %d shuffles", len(s.Shoes), len(seeds))
+	}
+	if w := opts.WhatIf; w != nil {
+		if w.AI == nil || w.Round < 1 || w.Round > nRounds || w.Seat < 0 || w.Seat >= nSeats {
+			return nil, fmt.Errorf("blackjack: no seat %d in round %d to substitute", w.Seat, w.Round)
+		}
+		nRounds = w.Round
+	}
+
+	g := New(Options{
+		Rules:       s.Rules,
+		Penetration: s.Penetration,
+		Deck:        s.Deck,
+		Shuffler:    &replayShuffler{seeds: seeds, shoes: s.Shoes},
+	})
+	ais := make([]AI, nSeats)
+	for i := range ais {
+		ais[i] = newReplayAI(&g, s.Events, i, opts.WhatIf)
+	}
+	g.sit(ais...)
+	var mismatch error
+	i := 0
+	g.Observe(ObserverFunc(func(e Event) {
+		if mismatch != nil || e.Type == EventMoveRefused || (opts.WhatIf != nil && e.Round >= opts.WhatIf.Round) {
+			return
+		}
+		for i < len(s.Events) && s.Events[i].Type == EventMoveRefused {
+			i++
+		}
+		if i >= len(s.Events) {
+			mismatch = fmt.Errorf("%w: unexpected %s event in round %d", ErrReplayMismatch, e.Type, e.Round)
+			return
+		}
+		if want := s.Events[i]; !sameEvent(want, e) {
+			mismatch = fmt.Errorf("%w: round %d: expected %s for seat %d, got %s for seat %d",
+				ErrReplayMismatch, e.Round, describe(want), want.Seat, describe(e), e.Seat)
+		}
+		i++
+	}))
+	g.Observe(opts.Observers...)
+	for r := 0; r < nRounds && mismatch == nil; r++ {
+		if err := playRound(&g); err != nil {
+			return g.Rounds(), err
+		}
+	}
+	for i < len(s.Events) && s.Events[i].Type == EventMoveRefused {
+		i++
+	}
+	if mismatch == nil && opts.WhatIf == nil && i != len(s.Events) {
+		mismatch = fmt.Errorf("%w: %d events were not replayed", ErrReplayMismatch, len(s.Events)-i)
+	}
+	return g.Rounds(), mismatch
+}
+
+// sameEvent reports whether a replayed event matches the recorded one.
+// Cards are compared by rank and suit only.
+func sameEvent(a, b Event) bool {
+	if a.Type != b.Type || a.Round != b.Round || a.Seat != b.Seat || a.Hand != b.Hand ||
+		a.Move != b.Move || a.Early != b.Early || a.Bet != b.Bet || a.Amount != b.Amount || a.Commitment != b.Commitment {
+		return false
+	}
+	if (a.Card == nil) != (b.Card == nil) || (a.Card != nil && a.Card.Face() != b.Card.Face()) {
+		return false
+	}
+	if len(a.Cards) != len(b.Cards) {
+		return false
+	}
+	for i := range a.Cards {
+		if a.Cards[i].Face() != b.Cards[i].Face() {
+			return false
+		}
+	}
+	return true
+}
+
+func describe(e Event) string {
+	switch {
+	case e.Card != nil:
+		return fmt.Sprintf("%s %s", e.Type, e.Card.Short())
+	case e.Move != "":
+		return fmt.Sprintf("%s %s", e.Type, e.Move)
+	case e.Type == EventRoundSettled:
+		return fmt.Sprintf("%s %v", e.Type, e.Amount)
+	}
+	return string(e.Type)
+}
+
+// replayShuffler stacks the recorded shoes, in order, or shuffles with the
+// recorded seeds if there are no shoes.
+type replayShuffler struct {
+	seeds []deck.Seed
+	shoes [][]deck.Card
+	n     int
+}
+
+func (s *replayShuffler) Shuffle(cards []deck.Card) deck.Seed {
+	n := s.n
+	s.n++
+	var seed deck.Seed
+	if n < len(s.seeds) {
+		seed = s.seeds[n]
+	}
+	if n < len(s.shoes) {
+		copy(cards, s.shoes[n])
+		return seed
+	}
+	return deck.FixedShuffler(seed).Shuffle(cards)
+}
+
+// moves maps the names of moves in events to the moves.
+var moves = map[string]Move{
+	"hit":       MoveHit,
+	"stand":     MoveStand,
+	"double":    MoveDouble,
+	"split":     MoveSplit,
+	"surrender": MoveSurrender,
+}
+
+// replayAI makes the recorded decisions of a seat, round by round, or hands
+// over to the AI of a WhatIf.
+type replayAI struct {
+	g         *Game
+	whatIf    *WhatIf
+	bets      map[int]int
+	insurance map[int]bool
+	moves     map[int][]Event
+	made      int
+}
+
+func newReplayAI(g *Game, events []Event, seat int, whatIf *WhatIf) *replayAI {
+	ai := &replayAI{
+		g:         g,
+		bets:      make(map[int]int),
+		insurance: make(map[int]bool),
+		moves:     make(map[int][]Event),
+	}
+	if whatIf != nil && whatIf.Seat == seat {
+		ai.whatIf = whatIf
+	}
+	for _, e := range events {
+		if e.Seat != seat {
+			continue
+		}
+		switch e.Type {
+		case EventBetPlaced:
+			ai.bets[e.Round] = e.Bet
+		case EventInsurance:
+			ai.insurance[e.Round] = true
+		case EventMoveTaken:
+			ai.moves[e.Round] = append(ai.moves[e.Round], e)
+		}
+	}
+	return ai
+}
+
+// substituted reports whether the WhatIf AI makes the next decision.
+func (ai *replayAI) substituted() bool {
+	return ai.whatIf != nil && ai.g.round == ai.whatIf.Round && ai.made >= ai.whatIf.Decision
+}
+
+func (ai *replayAI) Bet() int {
+	ai.made = 0
+	return ai.bets[ai.g.round]
+}
+
+func (ai *replayAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	if ai.substituted() {
+		if insurer, ok := ai.whatIf.AI.(Insurer); ok {
+			return insurer.Insurance(hand, dealer)
+		}
+		return false
+	}
+	return ai.insurance[ai.g.round]
+}
+
+func (ai *replayAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	if ai.substituted() {
+		return ai.whatIf.AI.Play(hand, dealer)
+	}
+	recorded := ai.moves[ai.g.round]
+	if ai.made >= len(recorded) {
+		return MoveStand
+	}
+	rec := recorded[ai.made]
+	move, ok := moves[rec.Move]
+	if !ok {
+		move = MoveStand
+	}
+	// the move is only counted once it is made, so that a move deferred
+	// until after the peek is asked for again; a surrender is only made
+	// early if it was recorded so
+	return func(g *Game) error {
+		if g.early && !rec.Early {
+			return errDeferred
+		}
+		err := move(g)
+		if err == nil {
+			ai.made++
+		}
+		return err
+	}
+}
+
+func (ai *replayAI) MoveError(err error) {
+	if ai.substituted() {
+		if h, ok := ai.whatIf.AI.(MoveErrorHandler); ok {
+			h.MoveError(err)
+		}
+	}
+}
+
+func (ai *replayAI) Results(hand [][]deck.Card, dealer []deck.Card) {
+	if ai.whatIf != nil && ai.g.round == ai.whatIf.Round {
+		ai.whatIf.AI.Results(hand, dealer)
+	}
+}
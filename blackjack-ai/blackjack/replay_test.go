@@ -0,0 +1,198 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"deck"
+)
+
+// capture plays a seeded game of rounds rounds and returns its session.
+func capture(t *testing.T, rules Rules, rounds int, ais ...AI) (*Game, *Session) {
+	g := New(Options{Rules: rules, Hands: rounds, Shuffler: deck.SeededShuffler(7)})
+	s := g.Capture()
+	if _, _, err := g.PlayTable(ais...); err != nil {
+		t.Fatal(err)
+	}
+	return &g, s
+}
+
+func TestReplay(t *testing.T) {
+	for _, rules := range []Rules{VegasStrip, AtlanticCity, European, {Surrender: SurrenderEarly}} {
+		g, s := capture(t, rules, 200,
+			&BasicStrategyAI{Rules: rules}, HiLoAI(rules), &BasicStrategyAI{Rules: VegasStrip, Unit: 3})
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var loaded Session
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			t.Fatal(err)
+		}
+		rounds, err := Replay(&loaded, ReplayOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := g.Rounds()
+		if len(rounds) != len(want) {
+			t.Fatalf("expected %d rounds, got: %d", len(want), len(rounds))
+		}
+		for i := range rounds {
+			if rounds[i].Winnings != want[i].Winnings {
+				t.Errorf("round %d: expected winnings of %v, got: %v", i, want[i].Winnings, rounds[i].Winnings)
+			}
+		}
+	}
+}
+
+func TestReplayPhysicalShuffle(t *testing.T) {
+	g := New(Options{
+		Rules:    VegasStrip,
+		Hands:    20,
+		Shuffler: deck.Procedure(deck.SeededShuffler(1), deck.CasinoShuffle),
+	})
+	s := g.Capture()
+	if _, _, err := g.PlayTable(&BasicStrategyAI{Rules: VegasStrip}, HiLoAI(VegasStrip)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Session
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Shoes) == 0 || loaded.Shoes[0][0] != s.Shoes[0][0] {
+		t.Fatalf("expected the shoes to be stored with their identity")
+	}
+	rounds, err := Replay(&loaded, ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range g.Rounds() {
+		if rounds[i].Winnings != want.Winnings {
+			t.Errorf("round %d: expected winnings of %v, got: %v", i, want.Winnings, rounds[i].Winnings)
+		}
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	_, s := capture(t, VegasStrip, 20, &BasicStrategyAI{Rules: VegasStrip})
+	for i, e := range s.Events {
+		if e.Type == EventMoveTaken && e.Seat == 0 && e.Move == "stand" {
+			s.Events[i].Move = "hit"
+			break
+		}
+	}
+	if _, err := Replay(s, ReplayOptions{}); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("expected %v, got: %v", ErrReplayMismatch, err)
+	}
+}
+
+type standAI struct{ scriptedAI }
+
+func TestReplayWhatIf(t *testing.T) {
+	_, s := capture(t, VegasStrip, 50, &BasicStrategyAI{Rules: VegasStrip})
+	round := 0
+	for _, e := range s.Events {
+		if e.Type == EventMoveTaken && e.Seat == 0 && e.Move == "hit" {
+			round = e.Round
+			break
+		}
+	}
+	if round == 0 {
+		t.Fatal("expected the player to hit at least once in 50 rounds")
+	}
+	var events []Event
+	rounds, err := Replay(s, ReplayOptions{
+		WhatIf:    &WhatIf{Round: round, Seat: 0, AI: &standAI{}},
+		Observers: []Observer{ObserverFunc(func(e Event) { events = append(events, e) })},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rounds) != round {
+		t.Fatalf("expected the replay to stop after round %d, got %d rounds", round, len(rounds))
+	}
+	if cards := rounds[round-1].Hands[0].Cards; len(cards) != 2 {
+		t.Errorf("expected the player to stand on two cards, got: %s", Hand(cards))
+	}
+	if last := events[len(events)-1]; last.Round != round || last.Type != EventRoundSettled {
+		t.Errorf("expected the observers to follow the replay, got: %+v", last)
+	}
+	if _, err := Replay(s, ReplayOptions{WhatIf: &WhatIf{Round: 51, AI: &standAI{}}}); err == nil {
+		t.Errorf("expected an error substituting a round that wasn't played")
+	}
+}
+
+func TestShuffledCommitment(t *testing.T) {
+	_, s := capture(t, VegasStrip, 100, &BasicStrategyAI{Rules: VegasStrip})
+	n := 0
+	for _, e := range s.Events {
+		if e.Type != EventShuffled {
+			continue
+		}
+		seed, err := deck.ParseSeed(s.Seeds[n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Commitment != seed.Commitment() {
+			t.Errorf("shuffle %d: expected the commitment of %s, got: %s", n, s.Seeds[n], e.Commitment)
+		}
+		n++
+	}
+	if n < 2 || n != len(s.Seeds) {
+		t.Errorf("expected a seed for each of the %d shuffles, got: %d", n, len(s.Seeds))
+	}
+}
+
+// lateSurrenderAI has its first move of every round, made before the peek
+// under early surrender, refused and then surrenders.
+type lateSurrenderAI struct {
+	scriptedAI
+	n int
+}
+
+func (ai *lateSurrenderAI) Bet() int {
+	ai.n = 0
+	return 1
+}
+
+func (ai *lateSurrenderAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	ai.n++
+	switch ai.n {
+	case 1:
+		return nil
+	case 2:
+		return MoveSurrender
+	}
+	return MoveStand
+}
+
+func TestReplayLateSurrenderUnderEarlyRules(t *testing.T) {
+	rules := Rules{Surrender: SurrenderEarly, Decks: 1}
+	g, s := capture(t, rules, 30, &BasicStrategyAI{Rules: rules}, &lateSurrenderAI{})
+	late := 0
+	for _, e := range s.Events {
+		if e.Type == EventMoveTaken && e.Seat == 1 && e.Move == "surrender" {
+			if e.Early {
+				t.Fatalf("expected the surrender to be made after the peek, got: %+v", e)
+			}
+			late++
+		}
+	}
+	if late == 0 {
+		t.Fatal("expected at least one surrender after the peek")
+	}
+	rounds, err := Replay(s, ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range g.Rounds() {
+		if rounds[i].Winnings != want.Winnings {
+			t.Errorf("round %d: expected winnings of %v, got: %v", i, want.Winnings, rounds[i].Winnings)
+		}
+	}
+}
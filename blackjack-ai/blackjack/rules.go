@@ -0,0 +1,114 @@
+package blackjack
+
+// DoubleRule restricts the hands a player may double down on.
+type DoubleRule int8
+
+const (
+	DoubleAny DoubleRule = iota
+	DoubleNineToEleven
+	DoubleTenToEleven
+)
+
+// SurrenderRule selects whether and when a player may surrender.
+type SurrenderRule int8
+
+const (
+	SurrenderNone SurrenderRule = iota
+	// SurrenderLate lets the player surrender once the dealer has checked
+	// for a blackjack.
+	SurrenderLate
+	// SurrenderEarly lets the player surrender before the dealer checks for
+	// a blackjack.
+	SurrenderEarly
+)
+
+// Rules are the rules of a blackjack table.
+//
+// The shoe holds Decks decks. The dealer hits soft 17 unless
+// DealerStandsSoft17 is set.
+//
+// A natural blackjack pays BlackJackPayout times the bet, e.g. 1.5 for 3:2 or
+// 1.2 for 6:5. By default the dealer peeks at the hole card for a blackjack
+// as in American casinos; with NoHoleCard the dealer only takes a second card
+// after the player is done, as in Europe, and a dealer blackjack takes every
+// bet on the table, including doubles and splits.
+//
+// DoubleOn restricts doubling down to some totals and DoubleAfterSplit allows
+// doubling down on hands created by a split.
+//
+// MaxSplitHands is the number of hands a player may split into, 4 if unset;
+// set it to 1 to forbid splitting. Split aces only receive one more card each
+// when SplitAcesOneCard is set, and may only be split again when ResplitAces
+// is set.
+//
+// Surrender selects whether a player may give up a hand for half the bet.
+type Rules struct {
+	Decks              int
+	DealerStandsSoft17 bool
+	BlackJackPayout    float64
+	NoHoleCard         bool
+	DoubleOn           DoubleRule
+	DoubleAfterSplit   bool
+	MaxSplitHands      int
+	SplitAcesOneCard   bool
+	ResplitAces        bool
+	Surrender          SurrenderRule
+}
+
+var (
+	// VegasStrip are the classic rules of the Las Vegas Strip: four decks,
+	// the dealer stands on soft 17 and doubling after splits is allowed.
+	VegasStrip = Rules{
+		Decks:              4,
+		DealerStandsSoft17: true,
+		BlackJackPayout:    1.5,
+		DoubleAfterSplit:   true,
+		MaxSplitHands:      4,
+		SplitAcesOneCard:   true,
+	}
+	// AtlanticCity are the rules of Atlantic City: eight decks, the dealer
+	// stands on soft 17, doubling after splits and late surrender are
+	// allowed.
+	AtlanticCity = Rules{
+		Decks:              8,
+		DealerStandsSoft17: true,
+		BlackJackPayout:    1.5,
+		DoubleAfterSplit:   true,
+		MaxSplitHands:      4,
+		SplitAcesOneCard:   true,
+		Surrender:          SurrenderLate,
+	}
+	// European are typical European rules: six decks, no hole card, doubling
+	// on 9 to 11 only and no resplitting.
+	European = Rules{
+		Decks:              6,
+		DealerStandsSoft17: true,
+		BlackJackPayout:    1.5,
+		NoHoleCard:         true,
+		DoubleOn:           DoubleNineToEleven,
+		DoubleAfterSplit:   true,
+		MaxSplitHands:      2,
+		SplitAcesOneCard:   true,
+	}
+	// Downtown are the rules of downtown Las Vegas: two decks and the dealer
+	// hits soft 17.
+	Downtown = Rules{
+		Decks:            2,
+		BlackJackPayout:  1.5,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    4,
+		SplitAcesOneCard: true,
+	}
+)
+
+func validateRules(rules *Rules) {
+	if rules.Decks <= 0 {
+		rules.Decks = 3
+	}
+	if rules.BlackJackPayout <= 0 {
+		rules.BlackJackPayout = 1.5
+	}
+	if rules.MaxSplitHands <= 0 {
+		rules.MaxSplitHands = 4
+	}
+}
@@ -0,0 +1,191 @@
+package blackjack
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"deck"
+)
+
+// SimOptions configures Simulate. Options are the options of the game played
+// by every worker; its Hands and Shuffler are ignored. Rounds rounds are
+// played in total, split between Workers goroutines, each with its own shoe
+// shuffled by NewShuffler, deck.SeededShuffler by default, from Seed plus the
+// index of the worker. Confidence is the level of SimResult.CI, 0.95 by
+// default. With History set the winnings of every round are kept in
+// SimResult.Bankroll, starting from Bankroll.
+type SimOptions struct {
+	Options
+	Rounds      int
+	Workers     int
+	Seed        int64
+	NewShuffler func(seed int64) deck.Shuffler
+	Confidence  float64
+	History     bool
+	Bankroll    float64
+}
+
+func validateSimOptions(opts *SimOptions) {
+	if opts.Rounds <= 0 {
+		opts.Rounds = 100000
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Workers > opts.Rounds {
+		opts.Workers = opts.Rounds
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.NewShuffler == nil {
+		opts.NewShuffler = deck.SeededShuffler
+	}
+	if opts.Confidence <= 0 || opts.Confidence >= 1 {
+		opts.Confidence = 0.95
+	}
+}
+
+// SimResult summarises a simulation. All the figures are per round, i.e. per
+// initial hand, split hands included in it.
+//
+// EV is the average winnings of a round and Variance and StdDev their
+// spread; StdErr is the standard error of EV and CI its confidence interval.
+// HouseEdge is what the house wins as a fraction of the initial bets.
+//
+// Bankroll is only set if SimOptions.History was; it holds the rounds of
+// every worker one after the other.
+type SimResult struct {
+	Rounds    int
+	Hands     int
+	Wagered   float64
+	Winnings  float64
+	EV        float64
+	HouseEdge float64
+	Variance  float64
+	StdDev    float64
+	StdErr    float64
+	CI        [2]float64
+	Bankroll  *Bankroll
+}
+
+// Simulate plays opts.Rounds rounds with a single seat and reports how the AI
+// fared. newAI is called once per worker, so that each has an AI of its own.
+// Games are silent but for what the AI prints itself, and a simulation with
+// the same options and seed always gives the same result. If a game gets
+// into an invalid state the simulation stops and returns the first error.
+func Simulate(opts SimOptions, newAI func() AI) (SimResult, error) {
+	validateSimOptions(&opts)
+	stats := make([]*simStats, opts.Workers)
+	errs := make([]error, opts.Workers)
+	var wg sync.WaitGroup
+	for w := range stats {
+		rounds := opts.Rounds / opts.Workers
+		if w < opts.Rounds%opts.Workers {
+			rounds++
+		}
+		gopts := opts.Options
+		gopts.Shuffler = opts.NewShuffler(opts.Seed + int64(w))
+		gopts.Output = nil
+		g := New(gopts)
+		g.sit(newAI())
+		stats[w] = &simStats{history: opts.History}
+		wg.Add(1)
+		go func(g *Game, st *simStats, rounds int, err *error) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if *err = playRound(g); *err != nil {
+					return
+				}
+				st.add(g.rounds[len(g.rounds)-1], g.seats[0].bet)
+				g.rounds = g.rounds[:0]
+			}
+		}(&g, stats[w], rounds, &errs[w])
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return SimResult{}, err
+		}
+	}
+
+	total := &simStats{}
+	for _, st := range stats {
+		total.merge(st)
+	}
+	ret := total.result(opts.Confidence)
+	if opts.History {
+		ret.Bankroll = &Bankroll{Start: opts.Bankroll}
+		for _, st := range stats {
+			ret.Bankroll.Winnings = append(ret.Bankroll.Winnings, st.winningsHistory...)
+		}
+	}
+	return ret, nil
+}
+
+// simStats keeps a running mean and variance of the winnings per round with
+// Welford's algorithm.
+type simStats struct {
+	n        int
+	hands    int
+	wagered  float64
+	winnings float64
+	mean     float64
+	m2       float64
+
+	history         bool
+	winningsHistory []float64
+}
+
+func (st *simStats) add(r Round, bet int) {
+	st.n++
+	st.hands += len(r.Hands)
+	st.wagered += float64(bet)
+	st.winnings += r.Winnings
+	if st.history {
+		st.winningsHistory = append(st.winningsHistory, r.Winnings)
+	}
+	delta := r.Winnings - st.mean
+	st.mean += delta / float64(st.n)
+	st.m2 += delta * (r.Winnings - st.mean)
+}
+
+// merge combines the statistics of another worker into st.
+func (st *simStats) merge(o *simStats) {
+	if o.n == 0 {
+		return
+	}
+	n := st.n + o.n
+	delta := o.mean - st.mean
+	st.mean += delta * float64(o.n) / float64(n)
+	st.m2 += o.m2 + delta*delta*float64(st.n)*float64(o.n)/float64(n)
+	st.n = n
+	st.hands += o.hands
+	st.wagered += o.wagered
+	st.winnings += o.winnings
+}
+
+func (st *simStats) result(confidence float64) SimResult {
+	ret := SimResult{
+		Rounds:   st.n,
+		Hands:    st.hands,
+		Wagered:  st.wagered,
+		Winnings: st.winnings,
+		EV:       st.mean,
+	}
+	if st.wagered > 0 {
+		ret.HouseEdge = -st.winnings / st.wagered
+	}
+	if st.n > 1 {
+		ret.Variance = st.m2 / float64(st.n-1)
+	}
+	ret.StdDev = math.Sqrt(ret.Variance)
+	if st.n > 0 {
+		ret.StdErr = ret.StdDev / math.Sqrt(float64(st.n))
+	}
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	ret.CI = [2]float64{ret.EV - z*ret.StdErr, ret.EV + z*ret.StdErr}
+	return ret
+}
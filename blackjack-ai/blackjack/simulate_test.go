@@ -0,0 +1,81 @@
+package blackjack
+
+import (
+	"math"
+	"testing"
+
+	"deck"
+)
+
+func TestSimulate(t *testing.T) {
+	opts := SimOptions{
+		Options: Options{Rules: VegasStrip},
+		Rounds:  20000,
+		Workers: 4,
+		Seed:    42,
+	}
+	newAI := func() AI { return &BasicStrategyAI{Rules: VegasStrip} }
+	res, err := Simulate(opts, newAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rounds != 20000 || res.Hands < res.Rounds {
+		t.Fatalf("expected %d rounds, got: %d rounds and %d hands", 20000, res.Rounds, res.Hands)
+	}
+	if math.Abs(res.EV-res.Winnings/float64(res.Rounds)) > 1e-9 {
+		t.Errorf("expected the EV to be the average winnings, got: %v", res.EV)
+	}
+	if res.CI[0] > res.EV || res.CI[1] < res.EV {
+		t.Errorf("expected the EV to be within its confidence interval, got: %v", res.CI)
+	}
+	// basic strategy is within a couple of percent of breaking even
+	if res.HouseEdge < -0.05 || res.HouseEdge > 0.05 {
+		t.Errorf("expected a house edge close to 0, got: %v", res.HouseEdge)
+	}
+	if res.StdDev < 0.9 || res.StdDev > 1.3 {
+		t.Errorf("expected a standard deviation of about 1.15, got: %v", res.StdDev)
+	}
+	if again, _ := Simulate(opts, newAI); again != res {
+		t.Errorf("expected the same seed to give the same result, got: %+v and %+v", res, again)
+	}
+}
+
+func TestSimulatePhysicalShuffles(t *testing.T) {
+	opts := SimOptions{Options: Options{Rules: VegasStrip}, Rounds: 2000, Workers: 2, Seed: 5}
+	newAI := func() AI { return &BasicStrategyAI{Rules: VegasStrip} }
+	uniform, err := Simulate(opts, newAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.NewShuffler = func(seed int64) deck.Shuffler {
+		return deck.Procedure(deck.SeededShuffler(seed), deck.CasinoShuffle)
+	}
+	casino, err := Simulate(opts, newAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if casino.Rounds != 2000 || casino == uniform {
+		t.Errorf("expected the casino shuffle to deal other rounds, got: %+v", casino)
+	}
+	if again, _ := Simulate(opts, newAI); again != casino {
+		t.Errorf("expected the same seed to give the same result, got: %+v and %+v", casino, again)
+	}
+}
+
+func TestSimStatsMerge(t *testing.T) {
+	all, a, b := &simStats{}, &simStats{}, &simStats{}
+	for i, w := range []float64{1, -1, 1.5, 0, -2, 2, -1, -0.5} {
+		r := Round{Hands: []HandResult{{}}, Winnings: w}
+		all.add(r, 1)
+		if i%2 == 0 {
+			a.add(r, 1)
+		} else {
+			b.add(r, 1)
+		}
+	}
+	a.merge(b)
+	want, got := all.result(0.95), a.result(0.95)
+	if math.Abs(want.Variance-got.Variance) > 1e-12 || want.Rounds != got.Rounds {
+		t.Errorf("expected merged statistics %+v, got: %+v", want, got)
+	}
+}
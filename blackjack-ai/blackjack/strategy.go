@@ -0,0 +1,247 @@
+package blackjack
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"deck"
+)
+
+// Action is what a strategy chart says to do with a hand. Most actions fall
+// back to another one when the rules or the hand don't allow them, e.g.
+// DoubleOrHit hits a hand of three cards.
+type Action uint8
+
+const (
+	noAction Action = iota
+	Hit
+	Stand
+	DoubleOrHit
+	DoubleOrStand
+	Split
+	SplitIfDAS
+	SurrenderOrHit
+	SurrenderOrStand
+	SurrenderOrSplit
+)
+
+var actionCodes = []string{"", "H", "S", "D", "Ds", "P", "Ph", "Rh", "Rs", "Rp"}
+
+// String returns the code of the action in a strategy chart, e.g. "Ds".
+func (a Action) String() string {
+	if int(a) < len(actionCodes) {
+		return actionCodes[a]
+	}
+	return fmt.Sprintf("Action(%d)", a)
+}
+
+// Strategy is a strategy chart: the action to take with every hard total,
+// soft total and pair against every dealer up card.
+//
+// Charts are written one hand per line, as "hard 16", "soft 18" or "pair A"
+// followed by the action codes against a dealer 2 through Ace. Lines starting
+// with # are comments. See strategies/basic.txt.
+type Strategy struct {
+	hard  [22][10]Action
+	soft  [22][10]Action
+	pairs [11][10]Action
+}
+
+//go:embed strategies/basic.txt
+var basicChart string
+
+// DefaultStrategy returns the basic strategy for the rules: the chart for 4 to
+// 8 decks with the dealer standing on soft 17, adjusted for a dealer who hits
+// soft 17, for one or two decks and for a dealer without a hole card.
+// Doubling after splits and surrendering are taken into account when the
+// strategy is played.
+func DefaultStrategy(rules Rules) *Strategy {
+	validateRules(&rules)
+	s, err := ParseStrategy(strings.NewReader(basicChart))
+	if err != nil {
+		panic(err)
+	}
+	if !rules.DealerStandsSoft17 {
+		s.hard[11][9] = DoubleOrHit
+		s.hard[15][9] = SurrenderOrHit
+		s.hard[17][9] = SurrenderOrStand
+		s.soft[18][0] = DoubleOrStand
+		s.soft[19][4] = DoubleOrStand
+		s.pairs[8][9] = SurrenderOrSplit
+	}
+	if rules.Decks <= 2 {
+		s.hard[9][0] = DoubleOrHit
+		s.hard[11][9] = DoubleOrHit
+		s.pairs[6][5] = SplitIfDAS
+		s.pairs[7][6] = SplitIfDAS
+	}
+	if rules.Decks == 1 {
+		s.hard[8][3] = DoubleOrHit
+		s.hard[8][4] = DoubleOrHit
+		s.soft[13][2] = DoubleOrHit
+		s.soft[18][9] = Stand
+	}
+	if rules.NoHoleCard {
+		// a dealer blackjack also takes doubles and splits
+		s.hard[11][8] = Hit
+		s.hard[11][9] = Hit
+		s.pairs[1][9] = Hit
+		s.pairs[8][8] = Hit
+		s.pairs[8][9] = Hit
+	}
+	return s
+}
+
+// LoadStrategy reads a strategy chart from a file.
+func LoadStrategy(path string) (*Strategy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseStrategy(f)
+}
+
+// ParseStrategy reads a strategy chart. Hands that are not listed are stood
+// on 17 or more and hit otherwise.
+func ParseStrategy(r io.Reader) (*Strategy, error) {
+	var s Strategy
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 12 {
+			return nil, fmt.Errorf("blackjack: line %d: expected a hand and 10 actions, got %d fields", line, len(fields))
+		}
+		row, err := s.row(fields[0], fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("blackjack: line %d: %v", line, err)
+		}
+		for i, code := range fields[2:] {
+			a, err := parseAction(code)
+			if err != nil {
+				return nil, fmt.Errorf("blackjack: line %d: %v", line, err)
+			}
+			row[i] = a
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// row returns the row of the chart for a hand such as "hard 16" or "pair A".
+func (s *Strategy) row(kind, hand string) (*[10]Action, error) {
+	if kind == "pair" {
+		switch hand {
+		case "A":
+			return &s.pairs[1], nil
+		case "T", "10":
+			return &s.pairs[10], nil
+		}
+		n, err := strconv.Atoi(hand)
+		if err != nil || n < 2 || n > 9 {
+			return nil, fmt.Errorf("invalid pair %q", hand)
+		}
+		return &s.pairs[n], nil
+	}
+	n, err := strconv.Atoi(hand)
+	switch {
+	case kind == "hard" && err == nil && n >= 4 && n <= 21:
+		return &s.hard[n], nil
+	case kind == "soft" && err == nil && n >= 12 && n <= 21:
+		return &s.soft[n], nil
+	case kind != "hard" && kind != "soft":
+		return nil, fmt.Errorf("invalid hand %q, expected hard, soft or pair", kind)
+	}
+	return nil, fmt.Errorf("invalid %s total %q", kind, hand)
+}
+
+func parseAction(code string) (Action, error) {
+	for a, c := range actionCodes {
+		if c != "" && strings.EqualFold(c, code) {
+			return Action(a), nil
+		}
+	}
+	return noAction, fmt.Errorf("invalid action %q", code)
+}
+
+// value returns the blackjack value of a card, counting an Ace as 1.
+func value(c deck.Card) int {
+	return min(int(c.Rank), 10)
+}
+
+// Action returns what the chart says to do with hand against the dealer's up
+// card.
+func (s *Strategy) Action(hand []deck.Card, dealer deck.Card) Action {
+	up := value(dealer) - 2
+	if up < 0 {
+		up = 9
+	}
+	if len(hand) == 2 && value(hand[0]) == value(hand[1]) {
+		if a := s.pairs[value(hand[0])][up]; a != noAction {
+			return a
+		}
+	}
+	return s.total(hand, up)
+}
+
+// total returns the action for the hand's total, ignoring pairs.
+func (s *Strategy) total(hand []deck.Card, up int) Action {
+	score := Score(hand...)
+	if score > 21 {
+		return Stand
+	}
+	a := s.hard[score][up]
+	if Soft(hand...) {
+		a = s.soft[score][up]
+	}
+	switch {
+	case a != noAction:
+		return a
+	case score >= 17:
+		return Stand
+	}
+	return Hit
+}
+
+// String writes the chart out in the format read by ParseStrategy.
+func (s *Strategy) String() string {
+	var sb strings.Builder
+	sb.WriteString("#         2  3  4  5  6  7  8  9  T  A\n")
+	write := func(hand string, row [10]Action) {
+		if row == [10]Action{} {
+			return
+		}
+		line := fmt.Sprintf("%-9s", hand)
+		for _, a := range row {
+			line += fmt.Sprintf(" %-2s", a)
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	for n := 4; n <= 21; n++ {
+		write(fmt.Sprintf("hard %d", n), s.hard[n])
+	}
+	for n := 12; n <= 21; n++ {
+		write(fmt.Sprintf("soft %d", n), s.soft[n])
+	}
+	for _, n := range []int{1, 10, 9, 8, 7, 6, 5, 4, 3, 2} {
+		label := strconv.Itoa(n)
+		switch n {
+		case 1:
+			label = "A"
+		case 10:
+			label = "T"
+		}
+		write("pair "+label, s.pairs[n])
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package blackjack
+
+import (
+	"strings"
+	"testing"
+
+	"deck"
+)
+
+func TestStrategyRoundTrip(t *testing.T) {
+	s := DefaultStrategy(VegasStrip)
+	parsed, err := ParseStrategy(strings.NewReader(s.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != *s {
+		t.Errorf("expected the chart to survive a round trip, got:\n%s", parsed)
+	}
+}
+
+func TestParseStrategyErrors(t *testing.T) {
+	tests := []string{
+		"hard 16 S S S S S H H H H",
+		"hard 3 H H H H H H H H H H",
+		"soft 22 S S S S S S S S S S",
+		"pair J P P P P P P P P P P",
+		"split 8 P P P P P P P P P P",
+		"hard 16 S S S S S H H X H H",
+	}
+	for _, chart := range tests {
+		if _, err := ParseStrategy(strings.NewReader(chart)); err == nil {
+			t.Errorf("%q: expected an error", chart)
+		}
+	}
+}
+
+func TestStrategyAction(t *testing.T) {
+	tests := []struct {
+		rules  Rules
+		hand   string
+		dealer string
+		want   Action
+	}{
+		{VegasStrip, "Ts 6d", "Kh", SurrenderOrHit},
+		{VegasStrip, "Ts 6d", "6h", Stand},
+		{VegasStrip, "Ts 2d 4c", "Kh", SurrenderOrHit},
+		{VegasStrip, "As 7d", "3h", DoubleOrStand},
+		{VegasStrip, "As 7d", "2h", Stand},
+		{Downtown, "As 7d", "2h", DoubleOrStand},
+		{VegasStrip, "8s 8d", "Ah", Split},
+		{Downtown, "8s 8d", "Ah", SurrenderOrSplit},
+		{European, "8s 8d", "Ah", Hit},
+		{VegasStrip, "Js Qd", "6h", Stand},
+		{VegasStrip, "2s 2d", "2h", SplitIfDAS},
+		{VegasStrip, "Ts 9d", "Ah", Stand},
+		{VegasStrip, "5s 4d 2c", "4h", DoubleOrHit},
+	}
+	for _, tt := range tests {
+		hand, err := deck.ParseHand(tt.hand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealer, err := deck.Parse(tt.dealer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := DefaultStrategy(tt.rules).Action(hand, dealer); got != tt.want {
+			t.Errorf("%s against %s: expected %s, got: %s", tt.hand, tt.dealer, tt.want, got)
+		}
+	}
+}
+
+func TestBasicStrategyAI(t *testing.T) {
+	g := newRound(t, Options{Rules: AtlanticCity}, "Ts Ts 6h 7d")
+	g.seats[0].ai = &BasicStrategyAI{Rules: AtlanticCity}
+	playerTurn(g)
+	finish(g)
+	if g.seats[0].balance != -0.5 {
+		t.Errorf("expected 16 against a 10 to be surrendered, got: %v", g.seats[0].balance)
+	}
+
+	// the AI thinks it may split, but the table doesn't allow it: it falls
+	// back on standing on 16 against a 6
+	g = newRound(t, Options{Rules: Rules{MaxSplitHands: 1}}, "8s 6s 8h Td 9c")
+	g.seats[0].ai = &BasicStrategyAI{Rules: VegasStrip}
+	playerTurn(g)
+	if h := g.seats[0].hands; len(h) != 1 || len(h[0].cards) != 2 || g.state != stateDealerTurn {
+		t.Errorf("expected the refused split to fall back on standing, got: %v", h)
+	}
+}
+
+func TestBasicStrategyAIPlays(t *testing.T) {
+	g := New(Options{Rules: VegasStrip, Hands: 2000})
+	if _, _, err := g.PlayTable(&BasicStrategyAI{Rules: VegasStrip}, &BasicStrategyAI{Rules: VegasStrip, Unit: 5}); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range g.Rounds() {
+		if r.Seat == 1 && r.Hands[0].Bet < 5 {
+			t.Fatalf("expected bets of at least 5, got: %d", r.Hands[0].Bet)
+		}
+	}
+}
@@ -3,15 +3,23 @@ package main
 import (
 	"blackjack-ai/blackjack"
 	"fmt"
+	"os"
 )
 
 func main() {
 	opts := blackjack.Options{
-		Decks:           3,
-		Hands:           2,
-		BlackJackPayout: 1.5,
+		Rules: blackjack.Rules{
+			Decks:           3,
+			BlackJackPayout: 1.5,
+		},
+		Hands:  2,
+		Output: os.Stdout,
 	}
 	game := blackjack.New(opts)
-	winnings := game.Play(blackjack.HumanAI())
+	winnings, _, err := game.Play(blackjack.HumanAI())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	fmt.Println("== Winnings == \n", winnings)
 }
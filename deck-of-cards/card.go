@@ -3,17 +3,23 @@ package deck
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
-	"time"
 )
 
 type Suit uint8
 type Rank uint8
 
+// Card is a playing card. Deck and Pos identify a card within a shoe: Deck
+// is the index of the deck it came from and Pos its position in the shoe when
+// it was last shuffled. They tell apart cards of the same rank and suit in a
+// multi-deck shoe; use Face to compare cards by rank and suit only.
 type Card struct {
 	Rank
 	Suit
+	Deck uint16
+	Pos  uint32
 }
 
 const (
@@ -55,6 +61,17 @@ func (c Card) String() string {
 	return fmt.Sprintf("%s of %ss", c.Rank.String(), c.Suit.String())
 }
 
+// Face returns the card without its identity, i.e. only its rank and suit.
+func (c Card) Face() Card {
+	return Card{Rank: c.Rank, Suit: c.Suit}
+}
+
+// ID returns a string that identifies the card within a shoe, e.g.
+// "As#2.117" for the Ace of Spades from deck 2 at position 117.
+func (c Card) ID() string {
+	return fmt.Sprintf("%s#%d.%d", c.Short(), c.Deck, c.Pos)
+}
+
 func DefaultSort(cards []Card) []Card {
 	sort.Slice(cards, Less(cards))
 	return cards
@@ -93,9 +110,12 @@ func New(opts ...func(c []Card) []Card) []Card {
 	return cards
 }
 
+// Shuffle returns an option that shuffles the cards. With a seed the order
+// comes from math/rand and is reproducible; without one every call uses
+// CryptoShuffler.
 func Shuffle(seed ...int64) func(cards []Card) []Card {
 	if len(seed) == 0 {
-		seed = append(seed, time.Now().Unix())
+		return ShuffleWith(CryptoShuffler())
 	}
 	return func(cards []Card) []Card {
 		var ret = make([]Card, len(cards))
@@ -108,14 +128,14 @@ func Shuffle(seed ...int64) func(cards []Card) []Card {
 	}
 }
 
+// Jokers returns an option that adds n jokers, ranked 1 to n.
 func Jokers(n int) func([]Card) []Card {
 	return func(cards []Card) []Card {
-		for n > 0 {
+		for i := 1; i <= n; i++ {
 			cards = append(cards, Card{
 				Suit: Joker,
-				Rank: Rank(n),
+				Rank: Rank(i),
 			})
-			n -= 1
 		}
 		return cards
 	}
@@ -133,12 +153,32 @@ func Filter(f func(card Card) bool) func(cards []Card) []Card {
 	}
 }
 
+// MaxDecks is the largest number of decks whose cards can be told apart.
+const MaxDecks = math.MaxUint16 + 1
+
+// Deck returns an option that makes n copies of the cards, setting the
+// Deck index of every card so that the copies can be told apart. It panics
+// if that would take more than MaxDecks indices.
 func Deck(n int) func(cards []Card) []Card {
 	return func(cards []Card) []Card {
-		var ret []Card
-		for n > 0 {
-			ret = append(ret, cards...)
-			n -= 1
+		per := 1
+		for _, card := range cards {
+			if int(card.Deck) >= per {
+				per = int(card.Deck) + 1
+			}
+		}
+		if n <= 0 {
+			return nil
+		}
+		if n > MaxDecks/per {
+			panic(fmt.Sprintf("deck: no more than %d decks can be told apart", MaxDecks))
+		}
+		ret := make([]Card, 0, n*len(cards))
+		for i := 0; i < n; i++ {
+			for _, card := range cards {
+				card.Deck = uint16(i*per) + card.Deck
+				ret = append(ret, card)
+			}
 		}
 		return ret
 	}
@@ -83,3 +83,50 @@ func TestDeck(t *testing.T) {
 		t.Errorf("expected %d, got: %d", 13*4*3, len(decks))
 	}
 }
+
+func TestOptionsAreReusable(t *testing.T) {
+	jokers, decks := Jokers(2), Deck(2)
+	for i := 0; i < 2; i++ {
+		if cards := New(jokers, decks); len(cards) != 54*2 {
+			t.Errorf("expected %d, got: %d", 54*2, len(cards))
+		}
+	}
+}
+
+func TestJokerRanks(t *testing.T) {
+	cards := New(Jokers(3))
+	for i, c := range cards[52:] {
+		if c.Rank != Rank(i+1) {
+			t.Errorf("expected joker rank %d, got: %d", i+1, c.Rank)
+		}
+	}
+}
+
+func TestDeckIndex(t *testing.T) {
+	cards := New(Deck(2), Deck(3))
+	seen := make(map[Card]bool)
+	for _, c := range cards {
+		if seen[c] {
+			t.Fatalf("%s appears twice", c.ID())
+		}
+		seen[c] = true
+	}
+	if cards[52].Face() != cards[0].Face() || cards[52].Deck != 1 {
+		t.Errorf("expected the second deck to have index 1, got: %d", cards[52].Deck)
+	}
+	if cards = New(Deck(300)); cards[len(cards)-1].Deck != 299 {
+		t.Errorf("expected the last of 300 decks to have index 299, got: %d", cards[len(cards)-1].Deck)
+	}
+}
+
+func TestDeckIndexOverflow(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected more than %d decks to panic", MaxDecks)
+		}
+	}()
+	if cards := New(Deck(-1)); len(cards) != 0 {
+		t.Errorf("expected no cards for -1 decks, got: %d", len(cards))
+	}
+	New(Deck(2), Deck(MaxDecks/2+1))
+}
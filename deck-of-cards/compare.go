@@ -0,0 +1,111 @@
+package deck
+
+import "sort"
+
+// Compare orders two cards. It returns a negative number when a sorts before
+// b, a positive number when a sorts after b and zero when this ordering does
+// not tell them apart.
+type Compare func(a, b Card) int
+
+var (
+	// RankAceLow orders cards by rank, with the Ace below the Two.
+	RankAceLow Compare = func(a, b Card) int {
+		return int(a.Rank) - int(b.Rank)
+	}
+	// RankAceHigh orders cards by rank, with the Ace above the King.
+	RankAceHigh Compare = func(a, b Card) int {
+		return aceHigh(a) - aceHigh(b)
+	}
+	// StandardSuits is the suit order New uses: spades, clubs, diamonds and
+	// hearts.
+	StandardSuits = SuitOrder(Spade, Club, Diamond, Heart)
+	// BridgeSuits is the suit order of bridge: clubs, diamonds, hearts and
+	// spades.
+	BridgeSuits = SuitOrder(Club, Diamond, Heart, Spade)
+
+	// SuitMajor groups cards by suit and orders each suit Ace to King. It
+	// matches DefaultSort.
+	SuitMajor = Then(StandardSuits, RankAceLow)
+	// RankMajor groups cards by rank, Ace to King, and orders each rank by
+	// suit.
+	RankMajor = Then(RankAceLow, StandardSuits)
+	// AceHigh groups cards by suit and orders each suit Two to Ace.
+	AceHigh = Then(StandardSuits, RankAceHigh)
+)
+
+func aceHigh(c Card) int {
+	if c.Rank == Ace && c.Suit != Joker {
+		return int(King) + 1
+	}
+	return int(c.Rank)
+}
+
+// SuitOrder orders cards by suit in the given order. Suits that are not
+// listed, including jokers, sort after the listed ones.
+func SuitOrder(order ...Suit) Compare {
+	var pos [Joker + 1]int
+	for i := range pos {
+		pos[i] = len(order) + i
+	}
+	for i, s := range order {
+		if int(s) < len(pos) {
+			pos[s] = i
+		}
+	}
+	index := func(s Suit) int {
+		if int(s) < len(pos) {
+			return pos[s]
+		}
+		return len(order) + int(s)
+	}
+	return func(a, b Card) int {
+		return index(a.Suit) - index(b.Suit)
+	}
+}
+
+// Trump orders cards of the trump suit after every other card. All other
+// cards compare equal, so it is usually combined with other orderings using
+// Then, e.g. Then(Trump(Heart), BridgeSuits, RankAceHigh).
+func Trump(trump Suit) Compare {
+	return func(a, b Card) int {
+		switch {
+		case a.Suit == trump && b.Suit != trump:
+			return 1
+		case a.Suit != trump && b.Suit == trump:
+			return -1
+		}
+		return 0
+	}
+}
+
+// Then combines orderings: cards are compared with each one in turn until
+// one of them tells the cards apart.
+func Then(cmps ...Compare) Compare {
+	return func(a, b Card) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// Reverse returns the opposite ordering of cmp.
+func Reverse(cmp Compare) Compare {
+	return func(a, b Card) int {
+		return cmp(b, a)
+	}
+}
+
+// SortBy returns an option that sorts the cards by the given orderings,
+// combined as with Then. Cards that compare equal keep their order.
+func SortBy(cmps ...Compare) func(cards []Card) []Card {
+	cmp := Then(cmps...)
+	return func(cards []Card) []Card {
+		sort.SliceStable(cards, func(i, j int) bool {
+			return cmp(cards[i], cards[j]) < 0
+		})
+		return cards
+	}
+}
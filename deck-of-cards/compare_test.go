@@ -0,0 +1,50 @@
+package deck
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleSortBy() {
+	hand, _ := ParseHand("Kh 2s Ah Td 2c")
+	fmt.Println(Symbols(false, SortBy(RankMajor)(hand)...))
+	fmt.Println(Symbols(false, SortBy(Trump(Heart), BridgeSuits, RankAceHigh)(hand)...))
+
+	// Output:
+	// A♥ 2♠ 2♣ 10♦ K♥
+	// 2♣ 10♦ 2♠ K♥ A♥
+}
+
+func TestSuitMajorMatchesDefaultSort(t *testing.T) {
+	want := New(Shuffle(3), DefaultSort)
+	got := New(Shuffle(3), SortBy(SuitMajor))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %s, got: %s", want[i], got[i])
+		}
+	}
+}
+
+func TestAceHigh(t *testing.T) {
+	cards := New(SortBy(AceHigh))
+	if cards[0] != (Card{Rank: Two, Suit: Spade}) || cards[12] != (Card{Rank: Ace, Suit: Spade}) {
+		t.Errorf("expected spades to run from two to ace, got: %s ... %s", cards[0], cards[12])
+	}
+}
+
+func TestSuitOrderJokersLast(t *testing.T) {
+	cards := New(Jokers(1), Shuffle(5), SortBy(BridgeSuits, RankAceLow))
+	if last := cards[len(cards)-1]; last.Suit != Joker {
+		t.Errorf("expected the joker last, got: %s", last)
+	}
+	if cards[0] != (Card{Rank: Ace, Suit: Club}) {
+		t.Errorf("expected the ace of clubs first, got: %s", cards[0])
+	}
+}
+
+func TestReverse(t *testing.T) {
+	cards := New(SortBy(Reverse(RankMajor)))
+	if cards[0] != (Card{Rank: King, Suit: Heart}) {
+		t.Errorf("expected the king of hearts first, got: %s", cards[0])
+	}
+}
@@ -0,0 +1,190 @@
+package deck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	rankChars = "A23456789TJQK"
+	suitChars = "scdh"
+	jokerCode = "Jk"
+)
+
+// Short returns the card in short notation: a rank character followed by a
+// suit character, e.g. "As", "Td" or "7h". Jokers are written "Jk" followed
+// by their rank, e.g. "Jk1".
+func (c Card) Short() string {
+	if c.Suit == Joker {
+		if c.Rank == 0 {
+			return jokerCode
+		}
+		return jokerCode + strconv.Itoa(int(c.Rank))
+	}
+	if !c.valid() {
+		return fmt.Sprintf("%%!Card(%d,%d)", c.Rank, c.Suit)
+	}
+	return string(rankChars[c.Rank-1]) + string(suitChars[c.Suit])
+}
+
+// valid reports whether c is a card that can be written in short notation
+// and encoded in a single byte.
+func (c Card) valid() bool {
+	if c.Suit == Joker {
+		return c.Rank <= 0xf
+	}
+	return c.Suit < Joker && c.Rank >= minRank && c.Rank <= maxRank
+}
+
+// Parse parses a card written in short notation. It also accepts "10" for
+// tens and is not case sensitive.
+func Parse(s string) (Card, error) {
+	if len(s) >= 2 && strings.EqualFold(s[:2], jokerCode) {
+		if len(s) == 2 {
+			return Card{Suit: Joker}, nil
+		}
+		n, err := strconv.ParseUint(s[2:], 10, 4)
+		if err != nil {
+			return Card{}, fmt.Errorf("deck: invalid joker %q", s)
+		}
+		return Card{Suit: Joker, Rank: Rank(n)}, nil
+	}
+	if len(s) < 2 {
+		return Card{}, fmt.Errorf("deck: invalid card %q", s)
+	}
+	rank, suit := s[:len(s)-1], s[len(s)-1:]
+	if rank == "10" {
+		rank = "T"
+	}
+	r := strings.Index(rankChars, strings.ToUpper(rank))
+	st := strings.Index(suitChars, strings.ToLower(suit))
+	if len(rank) != 1 || r < 0 || st < 0 {
+		return Card{}, fmt.Errorf("deck: invalid card %q", s)
+	}
+	return Card{Rank: Rank(r + 1), Suit: Suit(st)}, nil
+}
+
+// ParseHand parses cards in short notation separated by spaces or commas,
+// e.g. "As Kd, Jk1".
+func ParseHand(s string) ([]Card, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	cards := make([]Card, 0, len(fields))
+	for _, f := range fields {
+		card, err := Parse(f)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// ParseID parses a card written with Card.ID, identity included.
+func ParseID(s string) (Card, error) {
+	i := strings.IndexByte(s, '#')
+	j := strings.LastIndexByte(s, '.')
+	if i < 0 || j < i {
+		return Card{}, fmt.Errorf("deck: invalid card id %q", s)
+	}
+	card, err := Parse(s[:i])
+	if err != nil {
+		return Card{}, err
+	}
+	d, err := strconv.ParseUint(s[i+1:j], 10, 16)
+	if err != nil {
+		return Card{}, fmt.Errorf("deck: invalid deck in card id %q", s)
+	}
+	pos, err := strconv.ParseUint(s[j+1:], 10, 32)
+	if err != nil {
+		return Card{}, fmt.Errorf("deck: invalid position in card id %q", s)
+	}
+	card.Deck, card.Pos = uint16(d), uint32(pos)
+	return card, nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the short notation.
+// This is also how cards are encoded to JSON. Like the binary encoding, it
+// only records the Face of the card.
+func (c Card) MarshalText() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("deck: invalid card %d of suit %d", c.Rank, c.Suit)
+	}
+	return []byte(c.Short()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. A card is encoded in a
+// single byte: the suit in the high four bits and the rank in the low four.
+func (c Card) MarshalBinary() ([]byte, error) {
+	b, err := encodeCard(c)
+	if err != nil {
+		return nil, err
+	}
+	return []byte{b}, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *Card) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return fmt.Errorf("deck: a card is encoded in 1 byte, got %d", len(data))
+	}
+	card, err := decodeCard(data[0])
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// EncodeCards encodes cards one byte per card, as MarshalBinary does.
+func EncodeCards(cards []Card) ([]byte, error) {
+	ret := make([]byte, len(cards))
+	for i, c := range cards {
+		b, err := encodeCard(c)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = b
+	}
+	return ret, nil
+}
+
+// DecodeCards decodes cards encoded with EncodeCards.
+func DecodeCards(data []byte) ([]Card, error) {
+	ret := make([]Card, len(data))
+	for i, b := range data {
+		card, err := decodeCard(b)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = card
+	}
+	return ret, nil
+}
+
+func encodeCard(c Card) (byte, error) {
+	if !c.valid() {
+		return 0, fmt.Errorf("deck: invalid card %d of suit %d", c.Rank, c.Suit)
+	}
+	return byte(c.Suit)<<4 | byte(c.Rank), nil
+}
+
+func decodeCard(b byte) (Card, error) {
+	c := Card{Suit: Suit(b >> 4), Rank: Rank(b & 0xf)}
+	if !c.valid() {
+		return Card{}, fmt.Errorf("deck: invalid card byte %#x", b)
+	}
+	return c, nil
+}
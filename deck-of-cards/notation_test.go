@@ -0,0 +1,134 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func ExampleCard_Short() {
+	fmt.Println(Card{Rank: Ace, Suit: Spade}.Short())
+	fmt.Println(Card{Rank: Ten, Suit: Diamond}.Short())
+	fmt.Println(Card{Rank: 1, Suit: Joker}.Short())
+
+	// Output:
+	// As
+	// Td
+	// Jk1
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]Card{
+		"As":  {Rank: Ace, Suit: Spade},
+		"td":  {Rank: Ten, Suit: Diamond},
+		"10h": {Rank: Ten, Suit: Heart},
+		"Kc":  {Rank: King, Suit: Club},
+		"Jk":  {Suit: Joker},
+		"Jk2": {Rank: 2, Suit: Joker},
+	}
+	for in, want := range tests {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q): expected %s, got: %s (%v)", in, want, got, err)
+		}
+	}
+	for _, in := range []string{"", "A", "Ax", "1s", "Jkx", "100s"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseHand(t *testing.T) {
+	hand, err := ParseHand("As Kd, 7h,Jk1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Card{{Rank: Ace, Suit: Spade}, {Rank: King, Suit: Diamond}, {Rank: Seven, Suit: Heart}, {Rank: 1, Suit: Joker}}
+	if len(hand) != len(want) {
+		t.Fatalf("expected %d cards, got: %d", len(want), len(hand))
+	}
+	for i := range want {
+		if hand[i] != want[i] {
+			t.Errorf("expected %s, got: %s", want[i], hand[i])
+		}
+	}
+}
+
+func TestShortRoundTrip(t *testing.T) {
+	for _, c := range New(Jokers(3)) {
+		got, err := Parse(c.Short())
+		if err != nil || got != c {
+			t.Errorf("expected %s, got: %s (%v)", c, got, err)
+		}
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{Decks: 3, Cards: []func([]Card) []Card{Jokers(2)}, Shuffler: SeededShuffler(1)})
+	for {
+		c, err := shoe.Draw()
+		if err != nil {
+			break
+		}
+		got, err := ParseID(c.ID())
+		if err != nil || got != c {
+			t.Fatalf("expected %s, got: %s (%v)", c.ID(), got.ID(), err)
+		}
+	}
+	for _, s := range []string{"As", "As#1", "As#x.2", "Zz#1.2", "As#1.-2"} {
+		if _, err := ParseID(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	hand := []Card{{Rank: Ace, Suit: Spade}, {Rank: Ten, Suit: Heart}}
+	b, err := json.Marshal(hand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `["As","Th"]` {
+		t.Errorf("expected %s, got: %s", `["As","Th"]`, b)
+	}
+	var got []Card
+	if err := json.Unmarshal(b, &got); err != nil || len(got) != 2 || got[1] != hand[1] {
+		t.Errorf("expected %v, got: %v (%v)", hand, got, err)
+	}
+}
+
+func TestEncodeCards(t *testing.T) {
+	cards := New(Jokers(2), Deck(2))
+	b, err := EncodeCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != len(cards) {
+		t.Errorf("expected one byte per card, got: %d bytes", len(b))
+	}
+	got, err := DecodeCards(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cards {
+		if got[i] != cards[i].Face() {
+			t.Errorf("expected %s, got: %s", cards[i], got[i])
+		}
+	}
+	if _, err := DecodeCards([]byte{0x0e}); err == nil {
+		t.Errorf("expected an error decoding an invalid byte")
+	}
+}
+
+func TestCardBinary(t *testing.T) {
+	c := Card{Rank: Queen, Suit: Heart}
+	b, err := c.MarshalBinary()
+	if err != nil || len(b) != 1 {
+		t.Fatalf("expected 1 byte, got: %v (%v)", b, err)
+	}
+	var got Card
+	if err := got.UnmarshalBinary(b); err != nil || got != c {
+		t.Errorf("expected %s, got: %s (%v)", c, got, err)
+	}
+}
@@ -0,0 +1,147 @@
+package deck
+
+// The shuffles in this file model how a dealer shuffles by hand. Unlike
+// Shuffle they do not produce a uniform permutation, which is the point:
+// chaining a few of them leaves the kind of clumps real shoes have.
+
+// Riffle returns an option that riffles the cards once using the
+// Gilbert–Shannon–Reeds model: the deck is cut binomially and cards drop
+// from each half with probability proportional to the size of that half.
+func Riffle(r Rand) func(cards []Card) []Card {
+	return func(cards []Card) []Card {
+		cut := 0
+		for range cards {
+			cut += r.Intn(2)
+		}
+		left, right := cards[:cut], cards[cut:]
+		ret := make([]Card, 0, len(cards))
+		for len(left) > 0 || len(right) > 0 {
+			if r.Intn(len(left)+len(right)) < len(left) {
+				ret = append(ret, left[0])
+				left = left[1:]
+			} else {
+				ret = append(ret, right[0])
+				right = right[1:]
+			}
+		}
+		return ret
+	}
+}
+
+// Strip returns an option that strips the cards once, the way an overhand
+// shuffle does: small packets are pulled off the top and stacked, so the
+// order of the packets is reversed while each packet stays intact.
+func Strip(r Rand) func(cards []Card) []Card {
+	return func(cards []Card) []Card {
+		ret := make([]Card, len(cards))
+		end := len(cards)
+		for rest := cards; len(rest) > 0; {
+			k := 1 + r.Intn(9)
+			if k > len(rest) {
+				k = len(rest)
+			}
+			copy(ret[end-k:end], rest[:k])
+			rest = rest[k:]
+			end -= k
+		}
+		return ret
+	}
+}
+
+// Box returns an option that box shuffles the cards: the deck is cut into
+// four roughly equal packets which are restacked in reverse order.
+func Box(r Rand) func(cards []Card) []Card {
+	return func(cards []Card) []Card {
+		n := len(cards)
+		cuts := []int{0, 0, 0, 0, n}
+		for i := 1; i < 4; i++ {
+			cuts[i] = i*n/4 + r.Intn(n/8+1) - n/16
+			if cuts[i] < cuts[i-1] {
+				cuts[i] = cuts[i-1]
+			}
+		}
+		ret := make([]Card, 0, n)
+		for i := 3; i >= 0; i-- {
+			ret = append(ret, cards[cuts[i]:cuts[i+1]]...)
+		}
+		return ret
+	}
+}
+
+// Wash returns an option that washes the cards, spreading them face down and
+// pushing them around: as many times as there are cards, a random card is
+// moved to a random spot nearby, so some cards move several times and others
+// not at all.
+func Wash(r Rand) func(cards []Card) []Card {
+	return func(cards []Card) []Card {
+		n := len(cards)
+		ret := make([]Card, n)
+		copy(ret, cards)
+		if n < 2 {
+			return ret
+		}
+		reach := n/4 + 1
+		for i := 0; i < n; i++ {
+			from := r.Intn(n)
+			to := from + r.Intn(2*reach+1) - reach
+			if to < 0 {
+				to = 0
+			}
+			if to >= n {
+				to = n - 1
+			}
+			card := ret[from]
+			if to > from {
+				copy(ret[from:to], ret[from+1:to+1])
+			} else {
+				copy(ret[to+1:from+1], ret[to:from])
+			}
+			ret[to] = card
+		}
+		return ret
+	}
+}
+
+// CasinoShuffle returns an option that chains the shuffles of a typical
+// casino procedure: riffle, riffle, strip, riffle and box.
+func CasinoShuffle(r Rand) func(cards []Card) []Card {
+	steps := []func([]Card) []Card{Riffle(r), Riffle(r), Strip(r), Riffle(r), Box(r)}
+	return func(cards []Card) []Card {
+		for _, step := range steps {
+			cards = step(cards)
+		}
+		return cards
+	}
+}
+
+// Procedure returns a Shuffler that applies the given physical shuffles, so
+// they can be used by a Shoe. The randomness for every shuffle comes from a
+// seed drawn from seeds, which makes the result as reproducible as seeds is.
+//
+// The seeds it returns only replay a shuffle through the same steps, with
+// ReplayProcedure; FixedShuffler would permute the cards differently.
+func Procedure(seeds Shuffler, steps ...func(r Rand) func([]Card) []Card) Shuffler {
+	return procedure{seeds, steps}
+}
+
+// ReplayProcedure returns a Shuffler that replays a shuffle of Procedure with
+// the same steps from its seed.
+func ReplayProcedure(seed Seed, steps ...func(r Rand) func([]Card) []Card) Shuffler {
+	return procedure{FixedShuffler(seed), steps}
+}
+
+type procedure struct {
+	seeds Shuffler
+	steps []func(r Rand) func([]Card) []Card
+}
+
+func (p procedure) Shuffle(cards []Card) Seed {
+	seed := p.seeds.Shuffle(nil)
+	r := NewRand(seed)
+	ret := cards
+	for _, step := range p.steps {
+		ret = step(r)(ret)
+	}
+	copy(cards, ret)
+	return seed
+}
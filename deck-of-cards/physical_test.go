@@ -0,0 +1,95 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+)
+
+func isPermutation(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[Card]int)
+	for i := range a {
+		count[a[i]]++
+		count[b[i]]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPhysicalShuffles(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	shuffles := map[string]func([]Card) []Card{
+		"riffle": Riffle(r),
+		"strip":  Strip(r),
+		"box":    Box(r),
+		"wash":   Wash(r),
+		"casino": CasinoShuffle(r),
+	}
+	for name, shuffle := range shuffles {
+		cards := New(Deck(2))
+		if shuffled := shuffle(New(Deck(2))); !isPermutation(cards, shuffled) {
+			t.Errorf("%s: expected a permutation of the deck", name)
+		}
+	}
+}
+
+func TestRiffleRisingSequences(t *testing.T) {
+	cards := New(Riffle(rand.New(rand.NewSource(3))))
+	// A single riffle of a sorted deck leaves at most two rising sequences,
+	// so card k+1 comes before card k at most once.
+	pos := make(map[int]int)
+	for i, c := range cards {
+		pos[absRank(c)] = i
+	}
+	descents := 0
+	for k := 1; k < len(cards); k++ {
+		if pos[k+1] < pos[k] {
+			descents++
+		}
+	}
+	if descents > 1 {
+		t.Errorf("expected at most 1 descent after a riffle, got: %d", descents)
+	}
+}
+
+func TestProcedure(t *testing.T) {
+	cards := New()
+	seed := Procedure(SeededShuffler(9), CasinoShuffle).Shuffle(cards)
+	if !isPermutation(cards, New()) {
+		t.Fatalf("expected a permutation of the deck")
+	}
+	replay := New(ShuffleWith(ReplayProcedure(seed, CasinoShuffle)))
+	for i := range cards {
+		if cards[i] != replay[i] {
+			t.Fatalf("expected %s, got %s", cards[i], replay[i])
+		}
+	}
+}
+
+func TestProcedureLoggedSeed(t *testing.T) {
+	var buf bytes.Buffer
+	cards := New(ShuffleWith(LogShuffles(Procedure(SeededShuffler(4), Riffle, Strip), log.New(&buf, "", 0))))
+	var logged string
+	if _, err := fmt.Sscanf(buf.String(), "shuffle seed=%64s", &logged); err != nil {
+		t.Fatalf("unexpected log %q: %v", buf.String(), err)
+	}
+	seed, err := ParseSeed(logged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := New(ShuffleWith(ReplayProcedure(seed, Riffle, Strip)))
+	for i := range cards {
+		if cards[i] != replay[i] {
+			t.Fatalf("expected the logged seed to replay the shuffle, got %s for %s at %d", replay[i], cards[i], i)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"deck"
+)
+
+// EquityOptions configures CalculateEquity. Players holds the two hole cards
+// of every player, Board the community cards dealt so far and Dead any cards
+// known to be out of play.
+//
+// When the number of ways to complete the board is at most ExactLimit every
+// one of them is evaluated; otherwise Trials random boards are dealt.
+type EquityOptions struct {
+	Players    [][]deck.Card
+	Board      []deck.Card
+	Dead       []deck.Card
+	Trials     int
+	Workers    int
+	Seed       int64
+	ExactLimit int
+}
+
+func validateEquityOptions(opts *EquityOptions) error {
+	if len(opts.Players) == 0 {
+		return errors.New("poker: at least one player is required")
+	}
+	if len(opts.Board) > 5 {
+		return fmt.Errorf("poker: the board has %d cards, at most 5 are allowed", len(opts.Board))
+	}
+	seen := make(map[deck.Card]bool)
+	check := func(c deck.Card) error {
+		face := c.Face()
+		if c.Suit == deck.Joker {
+			return errors.New("poker: jokers are not allowed")
+		}
+		if seen[face] {
+			return fmt.Errorf("poker: %s is used more than once", face.Short())
+		}
+		seen[face] = true
+		return nil
+	}
+	for i, hole := range opts.Players {
+		if len(hole) != 2 {
+			return fmt.Errorf("poker: player %d has %d hole cards, 2 are required", i, len(hole))
+		}
+		for _, c := range hole {
+			if err := check(c); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range append(append([]deck.Card{}, opts.Board...), opts.Dead...) {
+		if err := check(c); err != nil {
+			return err
+		}
+	}
+	if opts.Trials <= 0 {
+		opts.Trials = 100000
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.ExactLimit <= 0 {
+		opts.ExactLimit = 100000
+	}
+	return nil
+}
+
+// Equity is how a single player fares over all the deals.
+type Equity struct {
+	// Win is the fraction of deals the player won outright.
+	Win float64
+	// Tie is the fraction of deals the player split with others.
+	Tie float64
+	// Equity is the player's average share of the pot.
+	Equity float64
+}
+
+// EquityResult holds the Equity of every player, in the order of
+// EquityOptions.Players.
+type EquityResult struct {
+	Players []Equity
+	Deals   int
+	Exact   bool
+}
+
+// CalculateEquity deals out the rest of the board, either exhaustively or by
+// Monte Carlo simulation spread across several goroutines, and reports how
+// often each player wins or ties.
+func CalculateEquity(opts EquityOptions) (EquityResult, error) {
+	if err := validateEquityOptions(&opts); err != nil {
+		return EquityResult{}, err
+	}
+	known := make(map[deck.Card]bool)
+	for _, hole := range opts.Players {
+		for _, c := range hole {
+			known[c.Face()] = true
+		}
+	}
+	for _, c := range append(append([]deck.Card{}, opts.Board...), opts.Dead...) {
+		known[c.Face()] = true
+	}
+	remaining := deck.New(deck.Filter(func(c deck.Card) bool {
+		return known[c]
+	}))
+	need := 5 - len(opts.Board)
+	if need > len(remaining) {
+		return EquityResult{}, errors.New("poker: not enough cards left to complete the board")
+	}
+
+	exact := combinations(len(remaining), need) <= opts.ExactLimit
+	tallies := make([]*tally, opts.Workers)
+	var wg sync.WaitGroup
+	for w := range tallies {
+		t := newTally(opts)
+		tallies[w] = t
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			cards := make([]deck.Card, len(remaining))
+			copy(cards, remaining)
+			if exact {
+				t.enumerate(cards, need, w, opts.Workers)
+				return
+			}
+			trials := opts.Trials / opts.Workers
+			if w < opts.Trials%opts.Workers {
+				trials++
+			}
+			t.sample(cards, need, trials, rand.New(rand.NewSource(opts.Seed+int64(w))))
+		}(w)
+	}
+	wg.Wait()
+
+	total := newTally(opts)
+	for _, t := range tallies {
+		total.deals += t.deals
+		for i := range total.wins {
+			total.wins[i] += t.wins[i]
+			total.ties[i] += t.ties[i]
+			total.shares[i] += t.shares[i]
+		}
+	}
+	ret := EquityResult{
+		Players: make([]Equity, len(opts.Players)),
+		Deals:   total.deals,
+		Exact:   exact,
+	}
+	if total.deals == 0 {
+		return ret, nil
+	}
+	n := float64(total.deals)
+	for i := range ret.Players {
+		ret.Players[i] = Equity{
+			Win:    float64(total.wins[i]) / n,
+			Tie:    float64(total.ties[i]) / n,
+			Equity: total.shares[i] / n,
+		}
+	}
+	return ret, nil
+}
+
+func combinations(n, k int) int {
+	ret := 1
+	for i := 0; i < k; i++ {
+		ret = ret * (n - i) / (i + 1)
+	}
+	return ret
+}
+
+type tally struct {
+	players [][]deck.Card
+	board   []deck.Card
+	known   int
+	hand    [7]deck.Card
+	ranks   []HandRank
+	deals   int
+	wins    []int
+	ties    []int
+	shares  []float64
+}
+
+func newTally(opts EquityOptions) *tally {
+	t := &tally{
+		players: opts.Players,
+		board:   make([]deck.Card, 5),
+		known:   len(opts.Board),
+		ranks:   make([]HandRank, len(opts.Players)),
+		wins:    make([]int, len(opts.Players)),
+		ties:    make([]int, len(opts.Players)),
+		shares:  make([]float64, len(opts.Players)),
+	}
+	copy(t.board, opts.Board)
+	return t
+}
+
+// score evaluates every player against the current board.
+func (t *tally) score() {
+	copy(t.hand[2:], t.board)
+	var best HandRank
+	winners := 0
+	for i, hole := range t.players {
+		t.hand[0], t.hand[1] = hole[0], hole[1]
+		t.ranks[i] = Evaluate(t.hand[:]...)
+		switch {
+		case t.ranks[i] > best:
+			best, winners = t.ranks[i], 1
+		case t.ranks[i] == best:
+			winners++
+		}
+	}
+	for i, r := range t.ranks {
+		if r != best {
+			continue
+		}
+		if winners == 1 {
+			t.wins[i]++
+		} else {
+			t.ties[i]++
+		}
+		t.shares[i] += 1 / float64(winners)
+	}
+	t.deals++
+}
+
+// sample deals trials random boards using a partial Fisher–Yates shuffle.
+func (t *tally) sample(cards []deck.Card, need, trials int, r *rand.Rand) {
+	for ; trials > 0; trials-- {
+		for i := 0; i < need; i++ {
+			j := i + r.Intn(len(cards)-i)
+			cards[i], cards[j] = cards[j], cards[i]
+			t.board[t.known+i] = cards[i]
+		}
+		t.score()
+	}
+}
+
+// enumerate deals every board whose first new card has an index congruent to
+// worker modulo workers, so that the workers share the work between them.
+func (t *tally) enumerate(cards []deck.Card, need, worker, workers int) {
+	if need == 0 {
+		if worker == 0 {
+			t.score()
+		}
+		return
+	}
+	for i := worker; i <= len(cards)-need; i += workers {
+		t.board[t.known] = cards[i]
+		t.fill(cards, i+1, t.known+1, need-1)
+	}
+}
+
+func (t *tally) fill(cards []deck.Card, start, pos, need int) {
+	if need == 0 {
+		t.score()
+		return
+	}
+	for i := start; i <= len(cards)-need; i++ {
+		t.board[pos] = cards[i]
+		t.fill(cards, i+1, pos+1, need-1)
+	}
+}
@@ -0,0 +1,75 @@
+package poker
+
+import (
+	"math"
+	"testing"
+
+	"deck"
+)
+
+func TestCalculateEquityMonteCarlo(t *testing.T) {
+	res, err := CalculateEquity(EquityOptions{
+		Players: [][]deck.Card{hand(t, "As Ah"), hand(t, "Kd Kc")},
+		Trials:  40000,
+		Seed:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Exact || res.Deals != 40000 {
+		t.Errorf("expected 40000 random deals, got: %d (exact: %v)", res.Deals, res.Exact)
+	}
+	// aces against kings preflop win about 82% of the time
+	if eq := res.Players[0].Equity; math.Abs(eq-0.82) > 0.02 {
+		t.Errorf("expected about 0.82 equity for aces, got: %.3f", eq)
+	}
+}
+
+func TestCalculateEquityExact(t *testing.T) {
+	res, err := CalculateEquity(EquityOptions{
+		Players: [][]deck.Card{hand(t, "Ah Kh"), hand(t, "Ad Kd")},
+		Board:   hand(t, "2c 3c 4s"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Exact || res.Deals != 45*44/2 {
+		t.Fatalf("expected %d exact deals, got: %d (exact: %v)", 45*44/2, res.Deals, res.Exact)
+	}
+	a, b := res.Players[0], res.Players[1]
+	if a != b {
+		t.Errorf("expected identical hands to have identical equity, got: %+v and %+v", a, b)
+	}
+	if math.Abs(a.Equity+b.Equity-1) > 1e-9 {
+		t.Errorf("expected the equities to add up to 1, got: %f", a.Equity+b.Equity)
+	}
+}
+
+func TestCalculateEquityRiver(t *testing.T) {
+	res, err := CalculateEquity(EquityOptions{
+		Players: [][]deck.Card{hand(t, "As Ks"), hand(t, "2c 2d"), hand(t, "7h 8h")},
+		Board:   hand(t, "Qs Js Ts 2h 3c"),
+		Dead:    hand(t, "9s"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Deals != 1 || res.Players[0].Win != 1 || res.Players[1].Equity != 0 {
+		t.Errorf("expected the royal flush to win, got: %+v", res)
+	}
+}
+
+func TestCalculateEquityErrors(t *testing.T) {
+	tests := []EquityOptions{
+		{},
+		{Players: [][]deck.Card{hand(t, "As")}},
+		{Players: [][]deck.Card{hand(t, "As Ks"), hand(t, "As Qs")}},
+		{Players: [][]deck.Card{hand(t, "As Ks")}, Board: hand(t, "2c 3c 4c 5c 6c 7c")},
+		{Players: [][]deck.Card{hand(t, "As Ks")}, Dead: hand(t, "Ks")},
+	}
+	for i, opts := range tests {
+		if _, err := CalculateEquity(opts); err == nil {
+			t.Errorf("test %d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Package poker ranks poker hands made of deck.Card.
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"deck"
+)
+
+// Category is the kind of a poker hand, from HighCard up to StraightFlush.
+type Category uint8
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var categoryNames = [...]string{
+	HighCard:      "High Card",
+	OnePair:       "One Pair",
+	TwoPair:       "Two Pair",
+	ThreeOfAKind:  "Three of a Kind",
+	Straight:      "Straight",
+	Flush:         "Flush",
+	FullHouse:     "Full House",
+	FourOfAKind:   "Four of a Kind",
+	StraightFlush: "Straight Flush",
+}
+
+func (c Category) String() string {
+	if int(c) < len(categoryNames) {
+		return categoryNames[c]
+	}
+	return fmt.Sprintf("Category(%d)", c)
+}
+
+// HandRank is the value of a poker hand. Hand ranks can be compared directly:
+// a greater HandRank beats a smaller one and equal ranks split the pot.
+//
+// The category is kept in the top bits, followed by up to five ranks that
+// break ties within the category, four bits each, most significant first.
+type HandRank uint32
+
+// Category returns the category of the hand, e.g. FullHouse.
+func (r HandRank) Category() Category {
+	return Category(r >> 20)
+}
+
+// Kickers returns the ranks that break ties within the category, most
+// significant first. For a full house that is the rank of the three of a
+// kind followed by the rank of the pair; for a straight it is its high card.
+func (r HandRank) Kickers() []deck.Rank {
+	var ret []deck.Rank
+	for shift := 16; shift >= 0; shift -= 4 {
+		v := (r >> uint(shift)) & 0xf
+		if v == 0 {
+			break
+		}
+		ret = append(ret, rankOf(int(v)-2))
+	}
+	return ret
+}
+
+func (r HandRank) String() string {
+	kickers := r.Kickers()
+	ks := make([]string, len(kickers))
+	for i, k := range kickers {
+		ks[i] = deck.Card{Rank: k, Suit: deck.Spade}.Short()[:1]
+	}
+	return fmt.Sprintf("%s (%s)", r.Category(), strings.Join(ks, " "))
+}
+
+// value returns the poker value of a rank, 0 for a Two up to 12 for an Ace.
+func value(r deck.Rank) int {
+	return (int(r) + 11) % 13
+}
+
+func rankOf(v int) deck.Rank {
+	return deck.Rank((v+1)%13 + 1)
+}
+
+const wheel = 1<<12 | 0xf // A, 2, 3, 4, 5
+
+var (
+	// straights maps a 13 bit set of values to one more than the value of the
+	// high card of the best straight in it, or 0 if there is none.
+	straights [1 << 13]uint8
+	// tops maps a 13 bit set of values to its five highest values encoded as
+	// kickers of a HandRank.
+	tops [1 << 13]uint32
+)
+
+func init() {
+	for m := 0; m < len(straights); m++ {
+		for high := 12; high >= 4; high-- {
+			run := 0x1f << uint(high-4)
+			if m&run == run {
+				straights[m] = uint8(high + 1)
+				break
+			}
+		}
+		if straights[m] == 0 && m&wheel == wheel {
+			straights[m] = 3 + 1
+		}
+		tops[m] = topN(uint16(m), 5)
+	}
+}
+
+func topN(m uint16, n int) uint32 {
+	var ret uint32
+	shift := 16
+	for ; n > 0 && m != 0; n-- {
+		v := bits.Len16(m) - 1
+		m &^= 1 << uint(v)
+		ret |= uint32(v+2) << uint(shift)
+		shift -= 4
+	}
+	return ret
+}
+
+func makeRank(c Category, kickers uint32) HandRank {
+	return HandRank(uint32(c)<<20 | kickers)
+}
+
+// Evaluate returns the rank of the best five card hand that can be made from
+// cards, which usually holds five to seven cards. Jokers are ignored.
+func Evaluate(cards ...deck.Card) HandRank {
+	var (
+		suits  [4]uint16
+		counts [13]uint8
+	)
+	for _, c := range cards {
+		if c.Suit == deck.Joker {
+			continue
+		}
+		v := value(c.Rank)
+		suits[c.Suit] |= 1 << uint(v)
+		counts[v]++
+	}
+	all := suits[0] | suits[1] | suits[2] | suits[3]
+
+	flush := uint16(0)
+	for _, m := range suits {
+		if bits.OnesCount16(m) >= 5 {
+			flush = m
+		}
+	}
+	if flush != 0 {
+		if high := straights[flush]; high != 0 {
+			return makeRank(StraightFlush, uint32(high+1)<<16)
+		}
+	}
+
+	var quads, trips, pairs uint16
+	for v, n := range counts {
+		switch {
+		case n >= 4:
+			quads |= 1 << uint(v)
+		case n == 3:
+			trips |= 1 << uint(v)
+		case n == 2:
+			pairs |= 1 << uint(v)
+		}
+	}
+
+	if quads != 0 {
+		q := topN(quads, 1)
+		return makeRank(FourOfAKind, q|topN(all&^highest(quads), 1)>>4)
+	}
+	if trips != 0 && bits.OnesCount16(trips)+bits.OnesCount16(pairs) >= 2 {
+		t := highest(trips)
+		return makeRank(FullHouse, topN(t, 1)|topN((trips&^t)|pairs, 1)>>4)
+	}
+	if flush != 0 {
+		return makeRank(Flush, tops[flush])
+	}
+	if high := straights[all]; high != 0 {
+		return makeRank(Straight, uint32(high+1)<<16)
+	}
+	if trips != 0 {
+		return makeRank(ThreeOfAKind, topN(trips, 1)|topN(all&^trips, 2)>>4)
+	}
+	if bits.OnesCount16(pairs) >= 2 {
+		p := topN(pairs, 2)
+		two := highest(pairs)
+		two |= highest(pairs &^ two)
+		return makeRank(TwoPair, p|topN(all&^two, 1)>>8)
+	}
+	if pairs != 0 {
+		return makeRank(OnePair, topN(pairs, 1)|topN(all&^pairs, 3)>>4)
+	}
+	return makeRank(HighCard, tops[all])
+}
+
+func highest(m uint16) uint16 {
+	if m == 0 {
+		return 0
+	}
+	return 1 << uint(bits.Len16(m)-1)
+}
+
+// Best returns the best five cards that can be made from cards along with
+// their rank. With Texas Hold'em that is the best 5 of the 7 cards formed by
+// the hole cards and the board.
+func Best(cards ...deck.Card) ([]deck.Card, HandRank) {
+	if len(cards) <= 5 {
+		ret := make([]deck.Card, len(cards))
+		copy(ret, cards)
+		return ret, Evaluate(cards...)
+	}
+	var (
+		best     = make([]deck.Card, 5)
+		bestRank HandRank
+		hand     = make([]deck.Card, 5)
+		idx      = []int{0, 1, 2, 3, 4}
+	)
+	for {
+		for i, j := range idx {
+			hand[i] = cards[j]
+		}
+		if rank := Evaluate(hand...); rank > bestRank {
+			bestRank = rank
+			copy(best, hand)
+		}
+		// advance to the next combination of 5 indices
+		i := 4
+		for i >= 0 && idx[i] == len(cards)-5+i {
+			i--
+		}
+		if i < 0 {
+			return best, bestRank
+		}
+		idx[i]++
+		for j := i + 1; j < 5; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package poker
+
+import (
+	"testing"
+
+	"deck"
+)
+
+func hand(t testing.TB, s string) []deck.Card {
+	cards, err := deck.ParseHand(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestEvaluateCategories(t *testing.T) {
+	tests := map[string]Category{
+		"As Ks Qs Js Ts":       StraightFlush,
+		"5d 4d 3d 2d Ad":       StraightFlush,
+		"9c 9d 9h 9s 2c":       FourOfAKind,
+		"Kc Kd Kh 9s 9c":       FullHouse,
+		"2h 7h 9h Jh Kh":       Flush,
+		"5c 6d 7h 8s 9c":       Straight,
+		"Ac 2d 3h 4s 5c":       Straight,
+		"Qc Qd Qh 4s 2c":       ThreeOfAKind,
+		"Jc Jd 4h 4s 2c":       TwoPair,
+		"Tc Td 8h 4s 2c":       OnePair,
+		"Ac Jd 8h 4s 2c":       HighCard,
+		"As Ad Ac Ks Kd Kc 2h": FullHouse,
+		"2s 3s 4s 5s 7s 6d Ah": Flush,
+		"2s 3s 4s 5s 7s 6s Ah": StraightFlush,
+	}
+	for h, want := range tests {
+		if got := Evaluate(hand(t, h)...).Category(); got != want {
+			t.Errorf("%s: expected %s, got: %s", h, want, got)
+		}
+	}
+}
+
+func TestEvaluateOrder(t *testing.T) {
+	// each hand beats the one after it
+	hands := []string{
+		"As Ks Qs Js Ts",
+		"6d 5d 4d 3d 2d",
+		"5d 4d 3d 2d Ad",
+		"Ac Ad Ah As Kc",
+		"Ac Ad Ah As Qc",
+		"2c 2d 2h As Ac",
+		"Ah Qh 9h 8h 7h",
+		"Ah Qh 9h 8h 6h",
+		"Tc Jd Qh Ks Ac",
+		"Ac 2d 3h 4s 5c",
+		"Kc Kd Kh As 2c",
+		"Kc Kd 9h 9s Ac",
+		"Kc Kd 9h 9s Qc",
+		"Kc Kd 8h 8s Ac",
+		"Ac Ad 9h 8s 7c",
+		"Ac Ad 9h 8s 6c",
+		"Ac Kd 9h 8s 7c",
+	}
+	for i := 1; i < len(hands); i++ {
+		a, b := Evaluate(hand(t, hands[i-1])...), Evaluate(hand(t, hands[i])...)
+		if a <= b {
+			t.Errorf("expected %s (%s) to beat %s (%s)", hands[i-1], a, hands[i], b)
+		}
+	}
+	if Evaluate(hand(t, "Ac Kd 9h 8s 7c")...) != Evaluate(hand(t, "Ad Kc 9s 8h 7d")...) {
+		t.Errorf("expected hands of the same ranks to tie")
+	}
+}
+
+func TestKickers(t *testing.T) {
+	rank := Evaluate(hand(t, "Kc Kd 9h 9s 9c 2d Ah")...)
+	kickers := rank.Kickers()
+	if len(kickers) != 2 || kickers[0] != deck.Nine || kickers[1] != deck.King {
+		t.Errorf("expected nines full of kings, got: %s", rank)
+	}
+	if s := rank.String(); s != "Full House (9 K)" {
+		t.Errorf("expected %q, got: %q", "Full House (9 K)", s)
+	}
+}
+
+func TestBest(t *testing.T) {
+	cards := hand(t, "Ah Kh 2c 7d Qh Jh Th")
+	best, rank := Best(cards...)
+	if rank.Category() != StraightFlush || len(best) != 5 {
+		t.Fatalf("expected a royal flush, got: %s", rank)
+	}
+	if Evaluate(best...) != rank {
+		t.Errorf("expected the best cards to have rank %s", rank)
+	}
+	if rank != Evaluate(cards...) {
+		t.Errorf("expected Best and Evaluate to agree")
+	}
+}
+
+func BenchmarkEvaluate7(b *testing.B) {
+	cards := deck.New(deck.Shuffle(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j := i % (len(cards) - 7)
+		Evaluate(cards[j : j+7]...)
+	}
+}
@@ -0,0 +1,135 @@
+package deck
+
+import "strings"
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+var suitSymbols = [...]string{
+	Spade:   "♠",
+	Club:    "♣",
+	Diamond: "♦",
+	Heart:   "♥",
+	Joker:   "🃏",
+}
+
+// Unicode code points of the ace of each suit in the Playing Cards block.
+var glyphBase = [...]rune{
+	Spade:   0x1F0A0,
+	Heart:   0x1F0B0,
+	Diamond: 0x1F0C0,
+	Club:    0x1F0D0,
+}
+
+// Red returns true for hearts and diamonds.
+func (s Suit) Red() bool {
+	return s == Heart || s == Diamond
+}
+
+// Symbol returns the suit symbol, e.g. "♠".
+func (s Suit) Symbol() string {
+	if int(s) < len(suitSymbols) {
+		return suitSymbols[s]
+	}
+	return s.String()
+}
+
+// Symbol returns the card as its rank followed by its suit symbol, e.g.
+// "A♠" or "10♥". Jokers are returned as "🃏".
+func (c Card) Symbol() string {
+	if c.Suit == Joker {
+		return c.Suit.Symbol()
+	}
+	return rankSymbol(c.Rank) + c.Suit.Symbol()
+}
+
+// Glyph returns the single Unicode playing card character for the card,
+// e.g. "🂡" for the Ace of Spades. Cards of an unknown suit are returned in
+// short notation.
+func (c Card) Glyph() string {
+	if c.Suit == Joker {
+		if c.Rank%2 == 0 {
+			return "\U0001F0DF"
+		}
+		return "\U0001F0CF"
+	}
+	if int(c.Suit) >= len(glyphBase) {
+		return c.Short()
+	}
+	offset := rune(c.Rank)
+	if c.Rank >= Queen {
+		// skip the Knight, which sits between the Jack and the Queen
+		offset++
+	}
+	return string(glyphBase[c.Suit] + offset)
+}
+
+// Colorize wraps s in an ANSI colour code if c is a red card.
+func Colorize(c Card, s string) string {
+	if !c.Suit.Red() {
+		return s
+	}
+	return ansiRed + s + ansiReset
+}
+
+// Symbols returns the cards in symbol form separated by spaces, with red
+// cards coloured if color is true.
+func Symbols(color bool, cards ...Card) string {
+	ret := make([]string, len(cards))
+	for i, c := range cards {
+		ret[i] = c.Symbol()
+		if color {
+			ret[i] = Colorize(c, ret[i])
+		}
+	}
+	return strings.Join(ret, " ")
+}
+
+// Art returns the cards drawn as ASCII boxes laid out side by side, one
+// line of output per row of the boxes, e.g.
+//
+//	+-----+ +-----+
+//	|A    | |10   |
+//	|  ♠  | |  ♥  |
+//	|    A| |   10|
+//	+-----+ +-----+
+func Art(color bool, cards ...Card) string {
+	rows := make([][]string, 5)
+	for _, c := range cards {
+		rank, suit := rankSymbol(c.Rank), c.Suit.Symbol()
+		if c.Suit == Joker {
+			rank, suit = "JK", "*"
+		}
+		lines := []string{
+			"+-----+",
+			"|" + rank + strings.Repeat(" ", 5-len(rank)) + "|",
+			"|  " + suit + "  |",
+			"|" + strings.Repeat(" ", 5-len(rank)) + rank + "|",
+			"+-----+",
+		}
+		for i, l := range lines {
+			if color {
+				l = Colorize(c, l)
+			}
+			rows[i] = append(rows[i], l)
+		}
+	}
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.WriteString(strings.Join(row, " "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func rankSymbol(r Rank) string {
+	if r == Ten {
+		return "10"
+	}
+	if r < minRank || r > maxRank {
+		return "?"
+	}
+	return string(rankChars[r-1])
+}
@@ -0,0 +1,52 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleSymbols() {
+	fmt.Println(Symbols(false, Card{Rank: Ace, Suit: Spade}, Card{Rank: Ten, Suit: Heart}))
+
+	// Output:
+	// A♠ 10♥
+}
+
+func ExampleArt() {
+	fmt.Print(Art(false, Card{Rank: Ace, Suit: Spade}, Card{Rank: Ten, Suit: Heart}))
+
+	// Output:
+	// +-----+ +-----+
+	// |A    | |10   |
+	// |  ♠  | |  ♥  |
+	// |    A| |   10|
+	// +-----+ +-----+
+}
+
+func TestGlyph(t *testing.T) {
+	tests := map[Card]string{
+		{Rank: Ace, Suit: Spade}:     "🂡",
+		{Rank: Jack, Suit: Heart}:    "🂻",
+		{Rank: Queen, Suit: Heart}:   "🂽",
+		{Rank: King, Suit: Diamond}:  "🃎",
+		{Rank: Two, Suit: Club}:      "🃒",
+		{Rank: 1, Suit: Joker}:       "🃏",
+		{Rank: Ace, Suit: Joker + 1}: "%!Card(1,5)",
+	}
+	for c, want := range tests {
+		if got := c.Glyph(); got != want {
+			t.Errorf("%s: expected %s, got: %s", c, want, got)
+		}
+	}
+}
+
+func TestColorize(t *testing.T) {
+	red := Symbols(true, Card{Rank: Ace, Suit: Diamond})
+	if !strings.HasPrefix(red, ansiRed) || !strings.HasSuffix(red, ansiReset) {
+		t.Errorf("expected red cards to be coloured, got: %q", red)
+	}
+	if black := Symbols(true, Card{Rank: Ace, Suit: Club}); black != "A♣" {
+		t.Errorf("expected black cards to be left alone, got: %q", black)
+	}
+}
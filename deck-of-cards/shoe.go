@@ -0,0 +1,154 @@
+package deck
+
+import "errors"
+
+// ErrEmptyShoe is returned when drawing more cards than a shoe has left.
+var ErrEmptyShoe = errors.New("deck: not enough cards left in the shoe")
+
+// ShoeOptions configures a Shoe. Cards are the options used to build a
+// single deck; the shoe then holds Decks copies of it, shuffled by Shuffler.
+type ShoeOptions struct {
+	Decks       int
+	Penetration float64
+	Cards       []func([]Card) []Card
+	Shuffler    Shuffler
+}
+
+func validateShoeOptions(opts *ShoeOptions) {
+	if opts.Decks <= 0 {
+		opts.Decks = 1
+	}
+	if opts.Penetration <= 0 || opts.Penetration > 1 {
+		opts.Penetration = 0.75
+	}
+	if opts.Shuffler == nil {
+		opts.Shuffler = CryptoShuffler()
+	}
+}
+
+// Shoe is a dealing shoe holding one or more shuffled decks. A cut card is
+// placed at the configured penetration; once it has been reached the shoe
+// reports that a reshuffle is due, but keeps dealing until it runs out.
+type Shoe struct {
+	opts        ShoeOptions
+	cards       []Card
+	dealt       int
+	cut         int
+	seed        Seed
+	onReshuffle []func()
+}
+
+// NewShoe builds and shuffles a new shoe.
+func NewShoe(opts ShoeOptions) *Shoe {
+	validateShoeOptions(&opts)
+	s := &Shoe{opts: opts}
+	s.Reshuffle()
+	return s
+}
+
+// Reshuffle gathers every card back into the shoe, shuffles it, places the
+// cut card and notifies anything registered with OnReshuffle.
+func (s *Shoe) Reshuffle() {
+	s.ReshuffleExcept()
+}
+
+// ReshuffleExcept reshuffles the shoe like Reshuffle, but leaves out cards
+// that are still in play, e.g. on the table when the shoe runs out in the
+// middle of a round. Cards are told apart by their face and Deck index.
+func (s *Shoe) ReshuffleExcept(inPlay ...Card) {
+	opts := make([]func([]Card) []Card, 0, len(s.opts.Cards)+1)
+	opts = append(opts, s.opts.Cards...)
+	opts = append(opts, Deck(s.opts.Decks))
+	s.cards = New(opts...)
+	if len(inPlay) > 0 {
+		out := make(map[Card]bool, len(inPlay))
+		for _, c := range inPlay {
+			c.Pos = 0
+			out[c] = true
+		}
+		cards := s.cards[:0]
+		for _, c := range s.cards {
+			if !out[c] {
+				cards = append(cards, c)
+			}
+		}
+		s.cards = cards
+	}
+	s.seed = s.opts.Shuffler.Shuffle(s.cards)
+	for i := range s.cards {
+		s.cards[i].Pos = uint32(i)
+	}
+	s.dealt = 0
+	s.cut = int(float64(len(s.cards)) * s.opts.Penetration)
+	for _, fn := range s.onReshuffle {
+		fn()
+	}
+}
+
+// Seed returns the seed of the most recent shuffle.
+func (s *Shoe) Seed() Seed {
+	return s.seed
+}
+
+// OnReshuffle registers fn to be called every time the shoe is reshuffled.
+func (s *Shoe) OnReshuffle(fn func()) {
+	s.onReshuffle = append(s.onReshuffle, fn)
+}
+
+// Draw deals the next card from the shoe.
+func (s *Shoe) Draw() (Card, error) {
+	if s.Remaining() == 0 {
+		return Card{}, ErrEmptyShoe
+	}
+	card := s.cards[s.dealt]
+	s.dealt++
+	return card, nil
+}
+
+// DrawN deals the next n cards from the shoe. If fewer than n cards are
+// left nothing is dealt and ErrEmptyShoe is returned.
+func (s *Shoe) DrawN(n int) ([]Card, error) {
+	if n < 0 {
+		return nil, errors.New("deck: cannot draw a negative number of cards")
+	}
+	if n > s.Remaining() {
+		return nil, ErrEmptyShoe
+	}
+	ret := make([]Card, n)
+	copy(ret, s.cards[s.dealt:s.dealt+n])
+	s.dealt += n
+	return ret, nil
+}
+
+// NeedsReshuffle returns true once the cut card has been reached.
+func (s *Shoe) NeedsReshuffle() bool {
+	return s.dealt >= s.cut
+}
+
+// Cards returns every card of the shoe in the order they are dealt since the
+// last shuffle, those already dealt included.
+func (s *Shoe) Cards() []Card {
+	ret := make([]Card, len(s.cards))
+	copy(ret, s.cards)
+	return ret
+}
+
+// Len returns the total number of cards in the shoe.
+func (s *Shoe) Len() int {
+	return len(s.cards)
+}
+
+// Dealt returns the number of cards dealt since the last shuffle.
+func (s *Shoe) Dealt() int {
+	return s.dealt
+}
+
+// Remaining returns the number of cards that have not been dealt yet.
+func (s *Shoe) Remaining() int {
+	return len(s.cards) - s.dealt
+}
+
+// Penetration returns the fraction of the shoe dealt before a reshuffle.
+func (s *Shoe) Penetration() float64 {
+	return s.opts.Penetration
+}
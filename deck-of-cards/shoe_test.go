@@ -0,0 +1,91 @@
+package deck
+
+import "testing"
+
+func TestNewShoe(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{Decks: 6, Penetration: 0.5})
+	if shoe.Len() != 13*4*6 {
+		t.Errorf("expected %d, got: %d", 13*4*6, shoe.Len())
+	}
+	if shoe.Remaining() != shoe.Len() || shoe.Dealt() != 0 {
+		t.Errorf("a new shoe shouldn't have any cards dealt")
+	}
+}
+
+func TestShoeDraw(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{})
+	for i := 0; i < 13*4; i++ {
+		if _, err := shoe.Draw(); err != nil {
+			t.Fatalf("unexpected error drawing card %d: %v", i, err)
+		}
+	}
+	if _, err := shoe.Draw(); err != ErrEmptyShoe {
+		t.Errorf("expected %v, got: %v", ErrEmptyShoe, err)
+	}
+}
+
+func TestShoeDrawN(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{})
+	cards, err := shoe.DrawN(50)
+	if err != nil || len(cards) != 50 {
+		t.Fatalf("expected 50 cards, got: %d (%v)", len(cards), err)
+	}
+	if _, err := shoe.DrawN(3); err != ErrEmptyShoe {
+		t.Errorf("expected %v, got: %v", ErrEmptyShoe, err)
+	}
+	if _, err := shoe.DrawN(-1); err == nil {
+		t.Errorf("expected an error drawing -1 cards")
+	}
+	if shoe.Remaining() != 2 {
+		t.Errorf("a failed DrawN shouldn't deal any cards")
+	}
+}
+
+func TestShoeReshuffle(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{Decks: 2, Penetration: 0.75})
+	reshuffles := 0
+	shoe.OnReshuffle(func() { reshuffles++ })
+	for !shoe.NeedsReshuffle() {
+		shoe.Draw()
+	}
+	if shoe.Dealt() != 78 {
+		t.Errorf("expected the cut card after %d cards, got: %d", 78, shoe.Dealt())
+	}
+	shoe.Reshuffle()
+	if reshuffles != 1 {
+		t.Errorf("expected %d reshuffle, got: %d", 1, reshuffles)
+	}
+	if shoe.NeedsReshuffle() || shoe.Remaining() != 104 {
+		t.Errorf("a reshuffled shoe should be full")
+	}
+}
+
+func TestShoeReshuffleExcept(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{Decks: 2})
+	inPlay, _ := shoe.DrawN(5)
+	shoe.ReshuffleExcept(inPlay...)
+	if shoe.Len() != 99 || shoe.Remaining() != 99 {
+		t.Fatalf("expected the 5 cards in play to be left out, got %d cards", shoe.Len())
+	}
+	for _, c := range shoe.Cards() {
+		for _, p := range inPlay {
+			if c.Face() == p.Face() && c.Deck == p.Deck {
+				t.Fatalf("expected %s to be left out", p.ID())
+			}
+		}
+	}
+}
+
+func TestShoePositions(t *testing.T) {
+	shoe := NewShoe(ShoeOptions{Decks: 2})
+	order := shoe.Cards()
+	for i := 0; i < shoe.Len(); i++ {
+		c, _ := shoe.Draw()
+		if int(c.Pos) != i {
+			t.Fatalf("expected position %d, got: %d", i, c.Pos)
+		}
+		if c != order[i] {
+			t.Fatalf("expected %s to be dealt, got: %s", order[i].ID(), c.ID())
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package deck
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"log"
+)
+
+// Seed is the randomness behind a single shuffle. Shuffling the same cards
+// with the same seed, the same way, always produces the same order, so a seed
+// that has been logged is enough to replay a shuffle exactly: with
+// FixedShuffler for the shufflers of this file, and with ReplayProcedure for
+// those of Procedure.
+type Seed [32]byte
+
+// String returns the seed as a hex string.
+func (s Seed) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Commitment returns the SHA-256 hash of the seed as a hex string. It can be
+// published before any cards are dealt and checked against the seed later.
+func (s Seed) Commitment() string {
+	sum := sha256.Sum256(s[:])
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseSeed parses a seed previously formatted with Seed.String.
+func ParseSeed(s string) (Seed, error) {
+	var seed Seed
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return seed, err
+	}
+	if len(b) != len(seed) {
+		return seed, errors.New("deck: seed must be 32 bytes")
+	}
+	copy(seed[:], b)
+	return seed, nil
+}
+
+// Rand is the source of randomness used by shuffles.
+type Rand interface {
+	// Intn returns a uniform random number in [0, n).
+	Intn(n int) int
+}
+
+// NewRand returns the deterministic Rand derived from seed. It is a
+// SHA-256 counter-mode stream, so it is unpredictable without the seed.
+func NewRand(seed Seed) Rand {
+	return &stream{key: seed, off: sha256.Size}
+}
+
+type stream struct {
+	key Seed
+	ctr uint64
+	buf [sha256.Size]byte
+	off int
+}
+
+func (s *stream) uint64() uint64 {
+	if s.off+8 > len(s.buf) {
+		var block [40]byte
+		copy(block[:], s.key[:])
+		binary.BigEndian.PutUint64(block[len(s.key):], s.ctr)
+		s.buf = sha256.Sum256(block[:])
+		s.ctr++
+		s.off = 0
+	}
+	v := binary.BigEndian.Uint64(s.buf[s.off:])
+	s.off += 8
+	return v
+}
+
+func (s *stream) Intn(n int) int {
+	if n <= 0 {
+		panic("deck: invalid argument to Intn")
+	}
+	max := ^uint64(0)
+	limit := max - max%uint64(n)
+	v := s.uint64()
+	for v >= limit {
+		v = s.uint64()
+	}
+	return int(v % uint64(n))
+}
+
+// Shuffler shuffles cards in place and returns the seed it used.
+type Shuffler interface {
+	Shuffle(cards []Card) Seed
+}
+
+func permute(cards []Card, r Rand) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// CryptoShuffler returns a Shuffler that draws a fresh seed from crypto/rand
+// for every shuffle. Use it for real play.
+func CryptoShuffler() Shuffler {
+	return cryptoShuffler{}
+}
+
+type cryptoShuffler struct{}
+
+func (cryptoShuffler) Shuffle(cards []Card) Seed {
+	var seed Seed
+	if _, err := rand.Read(seed[:]); err != nil {
+		panic("deck: unable to read random seed: " + err.Error())
+	}
+	permute(cards, NewRand(seed))
+	return seed
+}
+
+// SeededShuffler returns a Shuffler whose sequence of shuffles is entirely
+// determined by seed. Use it for tests and reproducible simulations.
+func SeededShuffler(seed int64) Shuffler {
+	return &seededShuffler{seed: seed}
+}
+
+type seededShuffler struct {
+	seed int64
+	n    uint64
+}
+
+func (s *seededShuffler) Shuffle(cards []Card) Seed {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(s.seed))
+	binary.BigEndian.PutUint64(b[8:], s.n)
+	s.n++
+	seed := Seed(sha256.Sum256(b[:]))
+	permute(cards, NewRand(seed))
+	return seed
+}
+
+// FixedShuffler returns a Shuffler that always uses seed. It replays a
+// shuffle that was logged earlier by CryptoShuffler or SeededShuffler.
+func FixedShuffler(seed Seed) Shuffler {
+	return fixedShuffler(seed)
+}
+
+type fixedShuffler Seed
+
+func (s fixedShuffler) Shuffle(cards []Card) Seed {
+	permute(cards, NewRand(Seed(s)))
+	return Seed(s)
+}
+
+// LogShuffles wraps s so that the seed and commitment of every shuffle are
+// written to l.
+func LogShuffles(s Shuffler, l *log.Logger) Shuffler {
+	return logShuffler{s, l}
+}
+
+type logShuffler struct {
+	Shuffler
+	l *log.Logger
+}
+
+func (s logShuffler) Shuffle(cards []Card) Seed {
+	seed := s.Shuffler.Shuffle(cards)
+	s.l.Printf("shuffle seed=%s commitment=%s", seed, seed.Commitment())
+	return seed
+}
+
+// ShuffleWith is an option for New that shuffles the cards with s.
+func ShuffleWith(s Shuffler) func(cards []Card) []Card {
+	return func(cards []Card) []Card {
+		s.Shuffle(cards)
+		return cards
+	}
+}
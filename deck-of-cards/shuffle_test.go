@@ -0,0 +1,68 @@
+package deck
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestSeededShuffler(t *testing.T) {
+	a := New(ShuffleWith(SeededShuffler(42)))
+	b := New(ShuffleWith(SeededShuffler(42)))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected the same order for the same seed, card %d differs", i)
+		}
+	}
+	c := New(ShuffleWith(SeededShuffler(43)))
+	same := true
+	for i := range a {
+		same = same && a[i] == c[i]
+	}
+	if same {
+		t.Errorf("expected a different order for a different seed")
+	}
+}
+
+func TestFixedShuffler(t *testing.T) {
+	cards := New()
+	seed := CryptoShuffler().Shuffle(cards)
+	replay := New(ShuffleWith(FixedShuffler(seed)))
+	for i := range cards {
+		if cards[i] != replay[i] {
+			t.Fatalf("expected %s, got %s", cards[i], replay[i])
+		}
+	}
+}
+
+func TestShuffleIsPermutation(t *testing.T) {
+	cards := New(Shuffle())
+	seen := make(map[Card]bool)
+	for _, c := range cards {
+		seen[c] = true
+	}
+	if len(cards) != 52 || len(seen) != 52 {
+		t.Errorf("expected 52 distinct cards, got: %d", len(seen))
+	}
+}
+
+func TestParseSeed(t *testing.T) {
+	seed := CryptoShuffler().Shuffle(New())
+	parsed, err := ParseSeed(seed.String())
+	if err != nil || parsed != seed {
+		t.Errorf("expected %s, got: %s (%v)", seed, parsed, err)
+	}
+	if _, err := ParseSeed("abcd"); err == nil {
+		t.Errorf("expected an error for a short seed")
+	}
+}
+
+func TestLogShuffles(t *testing.T) {
+	var buf bytes.Buffer
+	shoe := NewShoe(ShoeOptions{Shuffler: LogShuffles(SeededShuffler(1), log.New(&buf, "", 0))})
+	want := "shuffle seed=" + shoe.Seed().String() + " commitment=" + shoe.Seed().Commitment()
+	if strings.TrimSpace(buf.String()) != want {
+		t.Errorf("expected %q, got: %q", want, buf.String())
+	}
+}
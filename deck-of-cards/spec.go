@@ -0,0 +1,61 @@
+package deck
+
+// Spec describes the cards a deck is made of. Fields left at their zero
+// value describe a standard deck: the four suits, Ace through King, a single
+// copy of every card and no jokers.
+type Spec struct {
+	Suits  []Suit
+	Ranks  []Rank
+	Copies int
+	Jokers int
+}
+
+var (
+	// Standard is the standard 52 card deck.
+	Standard = Spec{}
+	// Spanish21 is a 48 card deck with the tens removed; the jacks, queens
+	// and kings are kept.
+	Spanish21 = Spec{Ranks: []Rank{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Jack, Queen, King}}
+	// Euchre is a 24 card deck running from Nine up to Ace.
+	Euchre = Spec{Ranks: []Rank{Nine, Ten, Jack, Queen, King, Ace}}
+	// Pinochle is a 48 card deck with two copies of every card from Nine up
+	// to Ace.
+	Pinochle = Spec{Ranks: []Rank{Nine, Ten, Jack, Queen, King, Ace}, Copies: 2}
+)
+
+// Cards returns the cards described by the spec.
+func (s Spec) Cards() []Card {
+	ss := s.Suits
+	if len(ss) == 0 {
+		ss = suits
+	}
+	ranks := s.Ranks
+	if len(ranks) == 0 {
+		for rank := minRank; rank <= maxRank; rank++ {
+			ranks = append(ranks, rank)
+		}
+	}
+	var cards []Card
+	for _, suit := range ss {
+		for _, rank := range ranks {
+			cards = append(cards, Card{Rank: rank, Suit: suit})
+		}
+	}
+	if s.Copies > 1 {
+		cards = Deck(s.Copies)(cards)
+	}
+	return Jokers(s.Jokers)(cards)
+}
+
+// Len returns the number of cards described by the spec.
+func (s Spec) Len() int {
+	return len(s.Cards())
+}
+
+// FromSpec is an option for New that replaces the cards with those described
+// by s, e.g. New(FromSpec(Euchre), Shuffle()).
+func FromSpec(s Spec) func(cards []Card) []Card {
+	return func([]Card) []Card {
+		return s.Cards()
+	}
+}
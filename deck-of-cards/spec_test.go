@@ -0,0 +1,43 @@
+package deck
+
+import "testing"
+
+func TestSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+		want int
+	}{
+		{"standard", Standard, 52},
+		{"spanish 21", Spanish21, 48},
+		{"euchre", Euchre, 24},
+		{"pinochle", Pinochle, 48},
+		{"two jokers", Spec{Jokers: 2}, 54},
+		{"hearts", Spec{Suits: []Suit{Heart}}, 13},
+	}
+	for _, tt := range tests {
+		if got := len(New(FromSpec(tt.spec))); got != tt.want {
+			t.Errorf("%s: expected %d cards, got: %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSpanish21(t *testing.T) {
+	for _, c := range New(FromSpec(Spanish21), Deck(6)) {
+		if c.Rank == Ten {
+			t.Fatalf("a Spanish 21 deck shouldn't have any tens")
+		}
+	}
+}
+
+func TestPinochle(t *testing.T) {
+	count := make(map[Card]int)
+	for _, c := range Pinochle.Cards() {
+		count[c.Face()]++
+	}
+	for c, n := range count {
+		if n != 2 || c.Rank > Ace && c.Rank < Nine {
+			t.Errorf("expected two of every card from nine to ace, got %d of %s", n, c)
+		}
+	}
+}